@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/checker"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/heartbeat"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/notifier"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/scheduler"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/systray"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/web"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to configuration file (YAML or TOML)")
+	menuBar := flag.Bool("menubar", false, "Show a system tray menu bar icon")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Pings for heartbeat checks are received by the web server
+	heartbeats := heartbeat.NewStore()
+
+	// Register all available checkers
+	registry := checker.NewRegistry()
+	registry.Register(checker.NewPingChecker())
+	registry.Register(checker.NewHTTPChecker())
+	registry.Register(checker.NewTCPChecker())
+	registry.Register(checker.NewDNSChecker())
+	registry.Register(checker.NewTLSChecker())
+	registry.Register(checker.NewGRPCChecker())
+	registry.Register(checker.NewExecChecker())
+	registry.Register(checker.NewHeartbeatChecker(heartbeats))
+	registry.Register(checker.NewRedisChecker())
+	registry.Register(checker.NewPostgresChecker())
+	registry.Register(checker.NewMySQLChecker())
+
+	server, err := web.NewServer(cfg, *configPath, cfg.WebServerPort)
+	if err != nil {
+		log.Fatalf("Failed to create web server: %v", err)
+	}
+	server.SetHeartbeats(heartbeats)
+
+	// Register all available notification channels
+	notifiers := notifier.NewRegistry()
+	notifiers.Register(notifier.NewWebhookNotifier())
+	notifiers.Register(notifier.NewSlackNotifier())
+	notifiers.Register(notifier.NewDiscordNotifier())
+	notifiers.Register(notifier.NewTeamsNotifier())
+	notifiers.Register(notifier.NewEmailNotifier())
+	notifiers.Register(notifier.NewPagerDutyNotifier())
+	notifiers.Register(notifier.NewOpsgenieNotifier())
+	notifiers.Register(notifier.NewNtfyNotifier())
+	notifiers.Register(notifier.NewGotifyNotifier())
+	notifiers.Register(notifier.NewPushoverNotifier())
+	notifiers.Register(notifier.NewHealthchecksNotifier())
+	if err := notifiers.Validate(cfg.Notifications); err != nil {
+		log.Fatalf("Invalid notification configuration: %v", err)
+	}
+	dispatcher := notifier.NewDispatcher(notifiers, server)
+
+	sched := scheduler.New(registry, server)
+	server.SetNextRuns(sched)
+	server.SetRunner(sched)
+	sched.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		server.UpdateResult(result, status)
+	})
+	// Report the confirmed state, not the raw result, so that isolated
+	// failures below fail_after do not trigger notifications
+	sched.AddHandler(dispatcher.Handle)
+	if cfg.EnableConsoleLog {
+		sched.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+			log.Printf("[%s] %s/%s: %s (%v)", result.Level, host.Name, check.Type, result.Message, result.Duration)
+			if status.Changed() {
+				log.Printf("%s/%s is now %s (was %s)", host.Name, check.Type, status.State, status.Previous)
+			}
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := server.Start(ctx); err != nil {
+			log.Printf("Web server stopped: %v", err)
+			stop()
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Printf("Scheduler started (default interval: %v, max concurrent checks: %d)", cfg.CheckInterval, cfg.MaxConcurrentChecks)
+		sched.Run(ctx)
+	}()
+
+	// The menu bar must run on the main goroutine
+	if *menuBar {
+		mb := systray.NewMenuBar(cfg.WebServerPort, stop)
+		go func() {
+			<-ctx.Done()
+			mb.Stop()
+		}()
+		mb.Run()
+	}
+
+	<-ctx.Done()
+	log.Println("Shutting down, waiting for in-flight checks to finish...")
+	wg.Wait()
+	dispatcher.Wait()
+	log.Println("Shutdown complete")
+}
@@ -3,6 +3,8 @@ package checker
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
 )
@@ -43,3 +45,49 @@ func (r *Registry) Get(checkType models.CheckType) (Checker, error) {
 func (r *Registry) GetAll() map[models.CheckType]Checker {
 	return r.checkers
 }
+
+// ApplyLatencyThresholds downgrades a passing result whose latency exceeds
+// the warn or critical threshold of the check. Results that are already worse
+// are left alone.
+func ApplyLatencyThresholds(check models.Check, result models.CheckResult) models.CheckResult {
+	if result.Level.Failed() || result.Duration <= 0 {
+		return result
+	}
+
+	latency := result.Duration.Round(time.Millisecond)
+	switch {
+	case check.Critical > 0 && result.Duration > time.Duration(check.Critical):
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("%s (latency %v exceeds critical %v)", result.Message, latency, check.Critical)
+	case check.Warn > 0 && result.Duration > time.Duration(check.Warn) && result.Level == models.LevelOK:
+		result.Level = models.LevelWarning
+		result.Message = fmt.Sprintf("%s (latency %v exceeds warn %v)", result.Message, latency, check.Warn)
+	}
+	return result
+}
+
+// intOption parses an integer option, returning def when it is not set
+func intOption(options map[string]string, key string, def int) (int, error) {
+	v, ok := options[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s: %s", key, v)
+	}
+	return n, nil
+}
+
+// durationOption parses a duration option, returning def when it is not set
+func durationOption(options map[string]string, key string, def time.Duration) (time.Duration, error) {
+	v, ok := options[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("Invalid %s: %s", key, v)
+	}
+	return d, nil
+}
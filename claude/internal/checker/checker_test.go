@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestApplyLatencyThresholds(t *testing.T) {
+	check := models.Check{
+		Type:     models.CheckTypeHTTP,
+		Warn:     models.Duration(100 * time.Millisecond),
+		Critical: models.Duration(time.Second),
+	}
+
+	tests := []struct {
+		name        string
+		check       models.Check
+		result      models.CheckResult
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:      "within thresholds",
+			check:     check,
+			result:    models.CheckResult{Level: models.LevelOK, Message: "HTTP 200", Duration: 50 * time.Millisecond},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "above warn",
+			check:       check,
+			result:      models.CheckResult{Level: models.LevelOK, Message: "HTTP 200", Duration: 200 * time.Millisecond},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "latency 200ms exceeds warn 100ms",
+		},
+		{
+			name:        "above critical",
+			check:       check,
+			result:      models.CheckResult{Level: models.LevelOK, Message: "HTTP 200", Duration: 2 * time.Second},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "latency 2s exceeds critical 1s",
+		},
+		{
+			name:        "warning from checker is kept",
+			check:       check,
+			result:      models.CheckResult{Level: models.LevelWarning, Message: "Certificate expires soon", Duration: 200 * time.Millisecond},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "Certificate expires soon",
+		},
+		{
+			name:        "failed result is left alone",
+			check:       check,
+			result:      models.CheckResult{Level: models.LevelCritical, Message: "Connection refused", Duration: 2 * time.Second},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Connection refused",
+		},
+		{
+			name:      "no thresholds",
+			check:     models.Check{Type: models.CheckTypeHTTP},
+			result:    models.CheckResult{Level: models.LevelOK, Message: "HTTP 200", Duration: 5 * time.Second},
+			wantLevel: models.LevelOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ApplyLatencyThresholds(tt.check, tt.result)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Expected level %s, got %s (message: %s)", tt.wantLevel, result.Level, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Expected message to contain %q, got %q", tt.wantMessage, result.Message)
+			}
+		})
+	}
+}
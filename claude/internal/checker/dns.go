@@ -0,0 +1,228 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+	"github.com/miekg/dns"
+)
+
+// resolvConfPath is used to find a resolver when none is configured
+const resolvConfPath = "/etc/resolv.conf"
+
+// dnsRecordTypes maps the supported record_type option values to DNS query types
+var dnsRecordTypes = map[string]uint16{
+	"A":     dns.TypeA,
+	"AAAA":  dns.TypeAAAA,
+	"CNAME": dns.TypeCNAME,
+	"MX":    dns.TypeMX,
+	"TXT":   dns.TypeTXT,
+	"SRV":   dns.TypeSRV,
+}
+
+// DNSChecker implements DNS resolution checks against a chosen resolver
+type DNSChecker struct{}
+
+// NewDNSChecker creates a new DNS checker
+func NewDNSChecker() *DNSChecker {
+	return &DNSChecker{}
+}
+
+// Type returns the checker type
+func (d *DNSChecker) Type() models.CheckType {
+	return models.CheckTypeDNS
+}
+
+// Check queries the configured resolver for records of the host address.
+//
+// Supported options:
+//   - resolver: resolver address as host or host:port (default: first nameserver in /etc/resolv.conf)
+//   - protocol: "udp" (default) or "tcp"
+//   - record_type: A (default), AAAA, CNAME, MX, TXT or SRV
+//   - expect: comma separated answers that must all be present
+//   - min_answers: minimum number of answers required
+//   - expect_nxdomain: "true" if the name is expected not to exist
+func (d *DNSChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeDNS,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	recordType := strings.ToUpper(check.Options["record_type"])
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dnsRecordTypes[recordType]
+	if !ok {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Unsupported record_type: %s", recordType)
+		return result
+	}
+
+	protocol := strings.ToLower(check.Options["protocol"])
+	if protocol == "" {
+		protocol = "udp"
+	}
+	if protocol != "udp" && protocol != "tcp" {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Unsupported protocol: %s (use udp or tcp)", protocol)
+		return result
+	}
+
+	minAnswers, err := intOption(check.Options, "min_answers", 0)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	expectNXDomain := check.Options["expect_nxdomain"] == "true"
+
+	resolver, err := resolverAddress(check.Options["resolver"])
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("No resolver available: %v", err)
+		return result
+	}
+
+	client := &dns.Client{
+		Net:     protocol,
+		Timeout: time.Duration(check.Timeout),
+	}
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(host.Address), qtype)
+	msg.RecursionDesired = true
+
+	start := time.Now()
+	resp, rtt, err := client.ExchangeContext(ctx, msg, resolver)
+	result.Duration = rtt
+	if err != nil {
+		result.Duration = time.Since(start)
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("DNS query to %s failed: %v", resolver, err)
+		return result
+	}
+
+	rcode := dns.RcodeToString[resp.Rcode]
+	if expectNXDomain {
+		if resp.Rcode == dns.RcodeNameError {
+			result.Level = models.LevelOK
+			result.Message = fmt.Sprintf("%s returned NXDOMAIN as expected (query time: %v)", host.Address, rtt)
+		} else {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Expected NXDOMAIN for %s, got %s", host.Address, rcode)
+		}
+		return result
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("DNS %s query for %s returned %s", recordType, host.Address, rcode)
+		return result
+	}
+
+	answers := dnsAnswers(resp, qtype)
+
+	if len(answers) < minAnswers || len(answers) == 0 {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("DNS %s query for %s returned %d answers (expected at least %d)", recordType, host.Address, len(answers), max(minAnswers, 1))
+		return result
+	}
+
+	if expect := check.Options["expect"]; expect != "" {
+		var missing []string
+		for _, want := range strings.Split(expect, ",") {
+			want = normalizeDNSAnswer(want)
+			if want == "" {
+				continue
+			}
+			found := false
+			for _, answer := range answers {
+				if normalizeDNSAnswer(answer) == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, want)
+			}
+		}
+		if len(missing) > 0 {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("DNS %s query for %s missing expected answers %s (got %s)", recordType, host.Address, strings.Join(missing, ", "), strings.Join(answers, ", "))
+			return result
+		}
+	}
+
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("DNS %s %s: %s (query time: %v)", recordType, host.Address, strings.Join(answers, ", "), rtt)
+	return result
+}
+
+// resolverAddress returns the resolver to query as host:port
+func resolverAddress(resolver string) (string, error) {
+	if resolver == "" {
+		conf, err := dns.ClientConfigFromFile(resolvConfPath)
+		if err != nil {
+			return "", err
+		}
+		if len(conf.Servers) == 0 {
+			return "", fmt.Errorf("no nameservers in %s", resolvConfPath)
+		}
+		return net.JoinHostPort(conf.Servers[0], conf.Port), nil
+	}
+
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		return net.JoinHostPort(resolver, "53"), nil
+	}
+	return resolver, nil
+}
+
+// dnsAnswers returns the answers of the requested type as strings
+func dnsAnswers(resp *dns.Msg, qtype uint16) []string {
+	var answers []string
+	for _, rr := range resp.Answer {
+		switch rec := rr.(type) {
+		case *dns.A:
+			if qtype == dns.TypeA {
+				answers = append(answers, rec.A.String())
+			}
+		case *dns.AAAA:
+			if qtype == dns.TypeAAAA {
+				answers = append(answers, rec.AAAA.String())
+			}
+		case *dns.CNAME:
+			if qtype == dns.TypeCNAME {
+				answers = append(answers, strings.TrimSuffix(rec.Target, "."))
+			}
+		case *dns.MX:
+			if qtype == dns.TypeMX {
+				answers = append(answers, strings.TrimSuffix(rec.Mx, "."))
+			}
+		case *dns.TXT:
+			if qtype == dns.TypeTXT {
+				answers = append(answers, strings.Join(rec.Txt, ""))
+			}
+		case *dns.SRV:
+			if qtype == dns.TypeSRV {
+				answers = append(answers, fmt.Sprintf("%s:%d", strings.TrimSuffix(rec.Target, "."), rec.Port))
+			}
+		}
+	}
+	return answers
+}
+
+// normalizeDNSAnswer makes answers comparable regardless of case and trailing dots
+func normalizeDNSAnswer(answer string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(answer), "."))
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+	"github.com/miekg/dns"
+)
+
+// startDNSServer starts a local resolver serving a small fixed zone over UDP and TCP
+func startDNSServer(t *testing.T) string {
+	t.Helper()
+
+	records := map[string][]string{
+		"web.test.":   {"web.test. 60 IN A 10.0.0.1", "web.test. 60 IN A 10.0.0.2"},
+		"alias.test.": {"alias.test. 60 IN CNAME web.test."},
+		"mail.test.":  {"mail.test. 60 IN MX 10 mx1.test."},
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		rrs, ok := records[q.Name]
+		if !ok {
+			resp.Rcode = dns.RcodeNameError
+		}
+		for _, s := range rrs {
+			rr, err := dns.NewRR(s)
+			if err == nil && rr.Header().Rrtype == q.Qtype {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+		w.WriteMsg(resp)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen on UDP: %v", err)
+	}
+	ln, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		pc.Close()
+		t.Fatalf("Failed to listen on TCP: %v", err)
+	}
+
+	udpServer := &dns.Server{PacketConn: pc, Handler: handler}
+	tcpServer := &dns.Server{Listener: ln, Handler: handler}
+	go udpServer.ActivateAndServe()
+	go tcpServer.ActivateAndServe()
+	t.Cleanup(func() {
+		udpServer.Shutdown()
+		tcpServer.Shutdown()
+	})
+
+	return pc.LocalAddr().String()
+}
+
+func TestDNSChecker(t *testing.T) {
+	resolver := startDNSServer(t)
+
+	tests := []struct {
+		name      string
+		address   string
+		options   map[string]string
+		wantLevel models.Level
+	}{
+		{
+			name:      "A record",
+			address:   "web.test",
+			options:   map[string]string{},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "A record over TCP with expected answers",
+			address:   "web.test",
+			options:   map[string]string{"protocol": "tcp", "expect": "10.0.0.1, 10.0.0.2"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "missing expected answer",
+			address:   "web.test",
+			options:   map[string]string{"expect": "10.0.0.3"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "min answers met",
+			address:   "web.test",
+			options:   map[string]string{"min_answers": "2"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "min answers not met",
+			address:   "web.test",
+			options:   map[string]string{"min_answers": "3"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "CNAME record",
+			address:   "alias.test",
+			options:   map[string]string{"record_type": "CNAME", "expect": "web.test."},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "MX record",
+			address:   "mail.test",
+			options:   map[string]string{"record_type": "mx", "expect": "mx1.test"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "no answers of requested type",
+			address:   "mail.test",
+			options:   map[string]string{"record_type": "AAAA"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "NXDOMAIN fails by default",
+			address:   "missing.test",
+			options:   map[string]string{},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "NXDOMAIN expected",
+			address:   "missing.test",
+			options:   map[string]string{"expect_nxdomain": "true"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "NXDOMAIN expected but name exists",
+			address:   "web.test",
+			options:   map[string]string{"expect_nxdomain": "true"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "unsupported record type",
+			address:   "web.test",
+			options:   map[string]string{"record_type": "PTR"},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewDNSChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"resolver": resolver}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeDNS,
+				Enabled: true,
+				Timeout: models.Duration(time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "dns", Address: tt.address}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+		})
+	}
+}
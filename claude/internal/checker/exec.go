@@ -0,0 +1,323 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// Nagios plugin exit codes
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// envOptionPrefix is the option key prefix for environment variables passed to the command
+const envOptionPrefix = "env."
+
+// execWaitDelay bounds how long to wait for output after the command exits or is killed
+const execWaitDelay = time.Second
+
+// ExecChecker runs external commands and interprets their result like a Nagios plugin
+type ExecChecker struct{}
+
+// NewExecChecker creates a new exec checker
+func NewExecChecker() *ExecChecker {
+	return &ExecChecker{}
+}
+
+// Type returns the checker type
+func (e *ExecChecker) Type() models.CheckType {
+	return models.CheckTypeExec
+}
+
+// Check runs the configured command and maps its exit code using Nagios plugin
+// semantics: 0 OK, 1 WARNING, 2 CRITICAL and 3 UNKNOWN. The first line of stdout
+// becomes the message and performance data after "|" is returned as metrics.
+// On timeout the command and its whole process group are killed.
+//
+// Supported options:
+//   - command: path of the command to run (required)
+//   - args: arguments, split on whitespace with single and double quote support
+//   - env.<NAME>: environment variable added to the inherited environment
+//   - workdir: working directory for the command
+func (e *ExecChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeExec,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	command := check.Options["command"]
+	if command == "" {
+		result.Level = models.LevelCritical
+		result.Message = "No command configured"
+		return result
+	}
+
+	args, err := splitArgs(check.Options["args"])
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Invalid args: %v", err)
+		return result
+	}
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = check.Options["workdir"]
+	cmd.Env = os.Environ()
+	for key, value := range check.Options {
+		if name, ok := strings.CutPrefix(key, envOptionPrefix); ok {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = execWaitDelay
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	err = cmd.Run()
+	result.Duration = time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		// Without a timeout of its own the command ran until the caller's deadline
+		timeout := time.Duration(check.Timeout)
+		if deadline, ok := ctx.Deadline(); ok && timeout <= 0 {
+			timeout = deadline.Sub(start).Round(100 * time.Millisecond)
+		}
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Command timed out after %v", timeout)
+		return result
+	}
+
+	exitCode := nagiosOK
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Failed to run command: %v", err)
+			return result
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	message, metrics := parseNagiosOutput(stdout.String())
+	if message == "" {
+		message, _, _ = strings.Cut(strings.TrimSpace(stderr.String()), "\n")
+	}
+	if message == "" {
+		message = fmt.Sprintf("No output (exit code %d)", exitCode)
+	}
+	result.Metrics = metrics
+
+	switch exitCode {
+	case nagiosOK:
+		result.Level = models.LevelOK
+		result.Message = message
+	case nagiosWarning:
+		result.Level = models.LevelWarning
+		result.Message = message
+	case nagiosCritical:
+		result.Level = models.LevelCritical
+		result.Message = message
+	case nagiosUnknown:
+		result.Level = models.LevelUnknown
+		result.Message = message
+	default:
+		result.Level = models.LevelUnknown
+		result.Message = fmt.Sprintf("Unexpected exit code %d: %s", exitCode, message)
+	}
+	return result
+}
+
+// splitArgs splits a command line into arguments. Whitespace separates arguments,
+// single quotes preserve their contents literally and double quotes allow backslash escapes.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// parseNagiosOutput returns the service output from the first line of plugin output
+// and the performance data found after "|" on the first line or in the long output.
+func parseNagiosOutput(output string) (string, []models.Metric) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	message, perfData, _ := strings.Cut(lines[0], "|")
+	perf := []string{perfData}
+
+	// Long output may carry additional performance data after its own "|",
+	// in which case every following line is performance data as well
+	for i, line := range lines[1:] {
+		if _, more, found := strings.Cut(line, "|"); found {
+			perf = append(perf, more)
+			perf = append(perf, lines[i+2:]...)
+			break
+		}
+	}
+
+	return strings.TrimSpace(message), parsePerfData(strings.Join(perf, " "))
+}
+
+// perfValueRe splits a performance data value into its number and unit of measurement
+var perfValueRe = regexp.MustCompile(`^([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)(.*)$`)
+
+// parsePerfData parses Nagios performance data of the form
+// 'label'=value[UOM];[warn];[crit];[min];[max]. Malformed entries are skipped.
+func parsePerfData(s string) []models.Metric {
+	var metrics []models.Metric
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		var label string
+		if strings.HasPrefix(s, "'") {
+			// Quoted labels may contain spaces and escape quotes by doubling them
+			var b strings.Builder
+			i := 1
+			for i < len(s) {
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			label = b.String()
+			s = s[min(i+1, len(s)):]
+		} else {
+			end := strings.IndexAny(s, "= ")
+			if end < 0 {
+				break
+			}
+			label = s[:end]
+			s = s[end:]
+		}
+
+		if !strings.HasPrefix(s, "=") {
+			// Not a label=value pair, skip to the next entry
+			if end := strings.IndexByte(s, ' '); end >= 0 {
+				s = s[end:]
+				continue
+			}
+			break
+		}
+		s = s[1:]
+
+		field := s
+		if end := strings.IndexAny(s, " \t"); end >= 0 {
+			field, s = s[:end], s[end:]
+		} else {
+			s = ""
+		}
+
+		parts := strings.Split(field, ";")
+		m := perfValueRe.FindStringSubmatch(parts[0])
+		if m == nil || label == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+
+		metric := models.Metric{Label: label, Value: value, Unit: m[2]}
+		if len(parts) > 1 {
+			metric.Warn = parts[1]
+		}
+		if len(parts) > 2 {
+			metric.Crit = parts[2]
+		}
+		if len(parts) > 3 {
+			metric.Min = parseOptionalFloat(parts[3])
+		}
+		if len(parts) > 4 {
+			metric.Max = parseOptionalFloat(parts[4])
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+// parseOptionalFloat parses s, returning nil when it is empty or not a number
+func parseOptionalFloat(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
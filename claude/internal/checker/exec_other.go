@@ -0,0 +1,9 @@
+//go:build !unix
+
+package checker
+
+import "os/exec"
+
+// setProcessGroup is a no-op on platforms without process groups; cancellation
+// only kills the command itself
+func setProcessGroup(cmd *exec.Cmd) {}
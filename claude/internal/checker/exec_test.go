@@ -0,0 +1,207 @@
+//go:build unix
+
+package checker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestExecChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "ok",
+			options:     map[string]string{"args": `-c 'echo "DISK OK - 40% used | used=40%;80;90"'`},
+			wantLevel:   models.LevelOK,
+			wantMessage: "DISK OK - 40% used",
+		},
+		{
+			name:        "warning",
+			options:     map[string]string{"args": `-c 'echo "DISK WARNING - 85% used"; exit 1'`},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "DISK WARNING - 85% used",
+		},
+		{
+			name:        "critical",
+			options:     map[string]string{"args": `-c 'echo "DISK CRITICAL - 95% used"; exit 2'`},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "DISK CRITICAL - 95% used",
+		},
+		{
+			name:        "unknown",
+			options:     map[string]string{"args": `-c 'echo "Invalid argument"; exit 3'`},
+			wantLevel:   models.LevelUnknown,
+			wantMessage: "Invalid argument",
+		},
+		{
+			name:        "unexpected exit code",
+			options:     map[string]string{"args": `-c 'echo oops; exit 42'`},
+			wantLevel:   models.LevelUnknown,
+			wantMessage: "Unexpected exit code 42: oops",
+		},
+		{
+			name:        "stderr used without stdout",
+			options:     map[string]string{"args": `-c 'echo "permission denied" >&2; exit 2'`},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "permission denied",
+		},
+		{
+			name:        "environment",
+			options:     map[string]string{"args": `-c 'echo "threshold=$THRESHOLD"'`, "env.THRESHOLD": "90"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "threshold=90",
+		},
+		{
+			name:        "working directory",
+			options:     map[string]string{"args": "-c pwd", "workdir": "/"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "/",
+		},
+		{
+			name:        "missing command",
+			options:     map[string]string{"command": "/nonexistent/check_foo"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Failed to run command",
+		},
+		{
+			name:        "unterminated quote",
+			options:     map[string]string{"args": `-c 'echo`},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Invalid args",
+		},
+	}
+
+	checker := NewExecChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"command": "/bin/sh"}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeExec,
+				Enabled: true,
+				Timeout: models.Duration(5 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "local"}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestExecCheckerMetrics(t *testing.T) {
+	check := models.Check{
+		Type:    models.CheckTypeExec,
+		Enabled: true,
+		Timeout: models.Duration(5 * time.Second),
+		Options: map[string]string{
+			"command": "/bin/sh",
+			"args":    `-c 'printf "LOAD OK | load1=0.5;4;8;0 load5=0.25;;\nlong output | x=1\nusers=3\n"'`,
+		},
+	}
+	result := NewExecChecker().Check(context.Background(), models.Host{Name: "local"}, check)
+	if result.Level != models.LevelOK || result.Message != "LOAD OK" {
+		t.Fatalf("Check() = %v %q, want OK with message %q", result.Level, result.Message, "LOAD OK")
+	}
+
+	var labels []string
+	for _, m := range result.Metrics {
+		labels = append(labels, m.Label)
+	}
+	if want := []string{"load1", "load5", "x", "users"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("Metric labels = %v, want %v", labels, want)
+	}
+}
+
+func TestExecCheckerCallerDeadline(t *testing.T) {
+	check := models.Check{
+		Type:    models.CheckTypeExec,
+		Enabled: true,
+		Options: map[string]string{"command": "/bin/sh", "args": "-c 'sleep 30'"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	result := NewExecChecker().Check(ctx, models.Host{Name: "local"}, check)
+	if want := "Command timed out after 500ms"; result.Level != models.LevelCritical || result.Message != want {
+		t.Errorf("Check() = %v %q, want %q", result.Level, result.Message, want)
+	}
+}
+
+func TestExecCheckerTimeoutKillsProcessGroup(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("No /proc filesystem to inspect the child process")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	check := models.Check{
+		Type:    models.CheckTypeExec,
+		Enabled: true,
+		Timeout: models.Duration(300 * time.Millisecond),
+		Options: map[string]string{
+			"command":      "/bin/sh",
+			"args":         `-c 'sleep 30 & echo $! > "$PID_FILE"; wait'`,
+			"env.PID_FILE": pidFile,
+		},
+	}
+
+	start := time.Now()
+	result := NewExecChecker().Check(context.Background(), models.Host{Name: "local"}, check)
+	if result.Level != models.LevelCritical || !strings.Contains(result.Message, "timed out") {
+		t.Fatalf("Check() = %v %q, want a timeout failure", result.Level, result.Message)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Check() took %v, want it to return shortly after the timeout", elapsed)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed to read child pid: %v", err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+
+	// The orphaned child may linger as a zombie until it is reaped, which still counts as killed
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+		if err != nil || strings.Contains(string(stat), ") Z ") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Child process %d still running after timeout", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestParsePerfData(t *testing.T) {
+	minVal, maxVal := 0.0, 100.0
+	got := parsePerfData(`'disk usage'=42.5%;80;90;0;100 time=0.12s 'it''s'=3 bad U=U`)
+	want := []models.Metric{
+		{Label: "disk usage", Value: 42.5, Unit: "%", Warn: "80", Crit: "90", Min: &minVal, Max: &maxVal},
+		{Label: "time", Value: 0.12, Unit: "s"},
+		{Label: "it's", Value: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePerfData() = %+v, want %+v", got, want)
+	}
+}
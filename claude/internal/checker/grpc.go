@@ -0,0 +1,145 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// metadataOptionPrefix is the option key prefix for gRPC metadata sent with the health RPC
+const metadataOptionPrefix = "metadata."
+
+// GRPCChecker implements checks using the standard gRPC health checking protocol
+type GRPCChecker struct{}
+
+// NewGRPCChecker creates a new gRPC checker
+func NewGRPCChecker() *GRPCChecker {
+	return &GRPCChecker{}
+}
+
+// Type returns the checker type
+func (g *GRPCChecker) Type() models.CheckType {
+	return models.CheckTypeGRPC
+}
+
+// Check calls grpc.health.v1.Health/Check on the configured port of the host.
+// Only a SERVING response is considered healthy.
+//
+// Supported options:
+//   - port: gRPC port to connect to (required)
+//   - service: service name to query (default: overall server health)
+//   - tls: "true" to connect with TLS instead of plaintext
+//   - tls_skip_verify: "true" to skip server certificate verification
+//   - ca_file: PEM CA bundle used to verify the server
+//   - server_name: name used for SNI and certificate verification (default: host address)
+//   - metadata.<key>: metadata sent with the request
+func (g *GRPCChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeGRPC,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	port := check.Options["port"]
+	if port == "" {
+		result.Level = models.LevelCritical
+		result.Message = "No port configured"
+		return result
+	}
+
+	creds := insecure.NewCredentials()
+	if check.Options["tls"] == "true" {
+		tlsConfig := &tls.Config{
+			ServerName:         check.Options["server_name"],
+			InsecureSkipVerify: check.Options["tls_skip_verify"] == "true",
+		}
+		if caFile := check.Options["ca_file"]; caFile != "" {
+			pool, err := loadCertPool(caFile)
+			if err != nil {
+				result.Level = models.LevelCritical
+				result.Message = err.Error()
+				return result
+			}
+			tlsConfig.RootCAs = pool
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	address := net.JoinHostPort(host.Address, port)
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Failed to create gRPC client: %v", err)
+		return result
+	}
+	defer conn.Close()
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	var pairs []string
+	for key, value := range check.Options {
+		if name, ok := strings.CutPrefix(key, metadataOptionPrefix); ok {
+			pairs = append(pairs, name, value)
+		}
+	}
+	if len(pairs) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+	}
+
+	service := check.Options["service"]
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	result.Duration = time.Since(start)
+
+	if err != nil {
+		result.Level = models.LevelCritical
+		switch status.Code(err) {
+		case codes.NotFound:
+			result.Message = fmt.Sprintf("Unknown service %q", service)
+		case codes.Unimplemented:
+			result.Message = "Health checking protocol not implemented by server"
+		default:
+			result.Message = fmt.Sprintf("Health check RPC failed: %v", err)
+		}
+		return result
+	}
+
+	switch resp.GetStatus() {
+	case healthpb.HealthCheckResponse_SERVING:
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("SERVING (response time: %v)", result.Duration)
+	case healthpb.HealthCheckResponse_NOT_SERVING:
+		result.Level = models.LevelCritical
+		result.Message = "NOT_SERVING"
+	case healthpb.HealthCheckResponse_UNKNOWN:
+		result.Level = models.LevelUnknown
+		result.Message = "Health status UNKNOWN"
+	default:
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Health status %s", resp.GetStatus())
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// startGRPCHealthServer starts a local gRPC server exposing the standard health service.
+// Requests carrying "x-reject" metadata are answered with NOT_SERVING.
+func startGRPCHealthServer(t *testing.T, opts ...grpc.ServerOption) (string, string) {
+	t.Helper()
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("reports", healthpb.HealthCheckResponse_UNKNOWN)
+
+	reject := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-reject")) > 0 {
+			return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+		}
+		return handler(ctx, req)
+	}
+
+	server := grpc.NewServer(append(opts, grpc.UnaryInterceptor(reject))...)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	addr, port, _ := net.SplitHostPort(ln.Addr().String())
+	return addr, port
+}
+
+func TestGRPCChecker(t *testing.T) {
+	addr, port := startGRPCHealthServer(t)
+
+	tests := []struct {
+		name        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "overall health",
+			options:     map[string]string{},
+			wantLevel:   models.LevelOK,
+			wantMessage: "SERVING",
+		},
+		{
+			name:      "serving service",
+			options:   map[string]string{"service": "orders"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "not serving service",
+			options:     map[string]string{"service": "billing"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "NOT_SERVING",
+		},
+		{
+			name:        "unknown status",
+			options:     map[string]string{"service": "reports"},
+			wantLevel:   models.LevelUnknown,
+			wantMessage: "UNKNOWN",
+		},
+		{
+			name:        "unregistered service",
+			options:     map[string]string{"service": "missing"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Unknown service",
+		},
+		{
+			name:        "metadata sent",
+			options:     map[string]string{"metadata.x-reject": "yes"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "NOT_SERVING",
+		},
+		{
+			name:      "TLS against plaintext server",
+			options:   map[string]string{"tls": "true", "tls_skip_verify": "true"},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewGRPCChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"port": port}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeGRPC,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "backend", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGRPCCheckerTLS(t *testing.T) {
+	// Borrow the self-signed httptest certificate for the gRPC server
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	cert := ts.TLS.Certificates[0]
+	ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	addr, port := startGRPCHealthServer(t, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+
+	tests := []struct {
+		name      string
+		options   map[string]string
+		wantLevel models.Level
+	}{
+		{
+			name:      "CA bundle",
+			options:   map[string]string{"tls": "true", "ca_file": caFile},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "server name override",
+			options:   map[string]string{"tls": "true", "ca_file": caFile, "server_name": "example.com"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "skip verify",
+			options:   map[string]string{"tls": "true", "tls_skip_verify": "true"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "unknown authority",
+			options:   map[string]string{"tls": "true"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "plaintext against TLS server",
+			options:   map[string]string{},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewGRPCChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"port": port}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeGRPC,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "backend", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+		})
+	}
+}
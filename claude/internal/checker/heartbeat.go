@@ -0,0 +1,129 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/heartbeat"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// HeartbeatChecker implements passive checks that evaluate pings sent by jobs
+// to the web server instead of probing the host
+type HeartbeatChecker struct {
+	store *heartbeat.Store
+	now   func() time.Time
+}
+
+// NewHeartbeatChecker creates a new heartbeat checker reading pings from store
+func NewHeartbeatChecker(store *heartbeat.Store) *HeartbeatChecker {
+	return &HeartbeatChecker{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Type returns the checker type
+func (h *HeartbeatChecker) Type() models.CheckType {
+	return models.CheckTypeHeartbeat
+}
+
+// Check reports whether a ping for the check's token arrived within period + grace.
+// The check fails when the last ping was a fail signal, or when a run sent a
+// start signal and did not finish within the start timeout.
+//
+// Supported options:
+//   - token: token used in the /ping/<token> URL (required)
+//   - period: expected time between pings, e.g. "1h" (required)
+//   - grace: additional time allowed before the check fails (default: 0)
+//   - start_timeout: how long a run may take after its start signal
+//     (default: grace). Without either, runs are not timed
+func (h *HeartbeatChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeHeartbeat,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	token := check.Options["token"]
+	if token == "" {
+		result.Level = models.LevelCritical
+		result.Message = "No token configured"
+		return result
+	}
+	period, err := durationOption(check.Options, "period", 0)
+	if err == nil && period <= 0 {
+		err = fmt.Errorf("No period configured")
+	}
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	grace, err := durationOption(check.Options, "grace", 0)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	startTimeout, err := durationOption(check.Options, "start_timeout", grace)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+
+	now := h.now()
+	state, pinged := h.store.Get(token)
+	result.Duration = state.RunTime
+
+	if startTimeout > 0 && !state.LastStart.IsZero() && now.Sub(state.LastStart) > startTimeout {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Job started %v ago and has not finished", roundAge(now.Sub(state.LastStart)))
+		return result
+	}
+
+	if state.Failed {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Job reported failure %v ago", roundAge(now.Sub(state.LastPing)))
+		if state.Message != "" {
+			result.Message += ": " + state.Message
+		}
+		return result
+	}
+
+	if !pinged || state.LastPing.IsZero() {
+		// Give jobs a full period to check in after startup
+		if waited := now.Sub(h.store.Created()); waited > period+grace {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("No ping received in %v", roundAge(waited))
+			return result
+		}
+		result.Level = models.LevelOK
+		result.Message = "Waiting for first ping"
+		return result
+	}
+
+	age := now.Sub(state.LastPing)
+	if age > period+grace {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Last ping %v ago (expected every %v)", roundAge(age), period)
+		return result
+	}
+
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("Last ping %v ago", roundAge(age))
+	return result
+}
+
+// roundAge rounds the time since a ping for display
+func roundAge(d time.Duration) time.Duration {
+	return d.Round(time.Second)
+}
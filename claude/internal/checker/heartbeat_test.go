@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/heartbeat"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestHeartbeatChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     map[string]string // Replaces the default options
+		pings       func(store *heartbeat.Store, now time.Time)
+		elapsed     time.Duration
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "waiting for first ping",
+			elapsed:     30 * time.Minute,
+			wantLevel:   models.LevelOK,
+			wantMessage: "Waiting for first ping",
+		},
+		{
+			name:        "never pinged",
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "No ping received",
+		},
+		{
+			name: "recent ping",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-50*time.Minute))
+			},
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelOK,
+			wantMessage: "Last ping 50m0s ago",
+		},
+		{
+			name: "ping within grace",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-65*time.Minute))
+			},
+			elapsed:   2 * time.Hour,
+			wantLevel: models.LevelOK,
+		},
+		{
+			name: "ping overdue",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-75*time.Minute))
+			},
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "expected every 1h0m0s",
+		},
+		{
+			name: "failure reported",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalFail, "disk full", now.Add(-time.Minute))
+			},
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Job reported failure 1m0s ago: disk full",
+		},
+		{
+			name: "recovered after failure",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalFail, "", now.Add(-time.Hour))
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-time.Minute))
+			},
+			elapsed:   2 * time.Hour,
+			wantLevel: models.LevelOK,
+		},
+		{
+			name: "run in progress",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-time.Hour))
+				store.Record("job", heartbeat.SignalStart, "", now.Add(-5*time.Minute))
+			},
+			elapsed:   2 * time.Hour,
+			wantLevel: models.LevelOK,
+		},
+		{
+			name: "run exceeded grace",
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalStart, "", now.Add(-20*time.Minute))
+			},
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "has not finished",
+		},
+		{
+			name:    "run without grace is not timed",
+			options: map[string]string{"token": "job", "period": "1h"},
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-30*time.Minute))
+				store.Record("job", heartbeat.SignalStart, "", now.Add(-20*time.Minute))
+			},
+			elapsed:   2 * time.Hour,
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:    "run within start timeout",
+			options: map[string]string{"token": "job", "period": "1h", "grace": "10m", "start_timeout": "30m"},
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalSuccess, "", now.Add(-50*time.Minute))
+				store.Record("job", heartbeat.SignalStart, "", now.Add(-20*time.Minute))
+			},
+			elapsed:   2 * time.Hour,
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:    "run exceeded start timeout",
+			options: map[string]string{"token": "job", "period": "1h", "start_timeout": "30m"},
+			pings: func(store *heartbeat.Store, now time.Time) {
+				store.Record("job", heartbeat.SignalStart, "", now.Add(-40*time.Minute))
+			},
+			elapsed:     2 * time.Hour,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Job started 40m0s ago and has not finished",
+		},
+	}
+
+	check := models.Check{
+		Type:    models.CheckTypeHeartbeat,
+		Enabled: true,
+		Options: map[string]string{"token": "job", "period": "1h", "grace": "10m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := heartbeat.NewStore()
+			now := store.Created().Add(tt.elapsed)
+			if tt.pings != nil {
+				tt.pings(store, now)
+			}
+
+			checker := NewHeartbeatChecker(store)
+			checker.now = func() time.Time { return now }
+
+			check := check
+			if tt.options != nil {
+				check.Options = tt.options
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "cron"}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHeartbeatCheckerRunTime(t *testing.T) {
+	store := heartbeat.NewStore()
+	start := time.Now()
+	store.Record("job", heartbeat.SignalStart, "", start)
+	store.Record("job", heartbeat.SignalSuccess, "", start.Add(42*time.Second))
+
+	check := models.Check{
+		Type:    models.CheckTypeHeartbeat,
+		Enabled: true,
+		Options: map[string]string{"token": "job", "period": "1h"},
+	}
+	result := NewHeartbeatChecker(store).Check(context.Background(), models.Host{Name: "cron"}, check)
+	if result.Level != models.LevelOK || result.Duration != 42*time.Second {
+		t.Errorf("Check() = %v, duration %v, want OK with the job run time of 42s", result.Level, result.Duration)
+	}
+}
@@ -3,18 +3,32 @@ package checker
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
 )
 
+// defaultUserAgent is sent unless the check overrides the User-Agent header
+const defaultUserAgent = "HealthChecker/1.0"
+
+// requestHeaderOptionPrefix is the option key prefix for custom request headers
+const requestHeaderOptionPrefix = "request_header."
+
 // HTTPChecker implements HTTP health checks
-type HTTPChecker struct{}
+type HTTPChecker struct {
+	clients    map[string]*http.Client
+	clientsMux sync.Mutex
+}
 
 // NewHTTPChecker creates a new HTTP checker
 func NewHTTPChecker() *HTTPChecker {
-	return &HTTPChecker{}
+	return &HTTPChecker{
+		clients: make(map[string]*http.Client),
+	}
 }
 
 // Type returns the checker type
@@ -22,7 +36,23 @@ func (h *HTTPChecker) Type() models.CheckType {
 	return models.CheckTypeHTTP
 }
 
-// Check performs an HTTP check on the host
+// Check performs an HTTP check on the host.
+//
+// Supported request options:
+//   - url: URL to request (default: http://<address>)
+//   - method: HTTP method (default: GET)
+//   - request_header.<name>: request header to send
+//   - body: request body
+//   - basic_auth_user / basic_auth_password: HTTP basic authentication
+//   - bearer_token: bearer token sent in the Authorization header
+//   - follow_redirects: "false" to report redirects instead of following them
+//   - max_redirects: maximum number of redirects to follow (default: 10)
+//   - tls_skip_verify: "true" to skip server certificate verification
+//   - ca_file: PEM CA bundle used to verify the server
+//   - client_cert / client_key: PEM client certificate and key for mTLS
+//   - proxy: HTTP or HTTPS proxy URL
+//
+// Response assertions are described in assertResponse.
 func (h *HTTPChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
 	result := models.CheckResult{
 		Host:      host.Name,
@@ -31,7 +61,7 @@ func (h *HTTPChecker) Check(ctx context.Context, host models.Host, check models.
 	}
 
 	if !check.Enabled {
-		result.Success = true
+		result.Level = models.LevelOK
 		result.Message = "Check disabled"
 		return result
 	}
@@ -51,42 +81,96 @@ func (h *HTTPChecker) Check(ctx context.Context, host models.Host, check models.
 		fmt.Sscanf(statusStr, "%d", &expectedStatus)
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(check.Timeout),
+	// Get a client matching the redirect, TLS and proxy options
+	client, err := h.clientFor(check.Options)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Invalid HTTP client options: %v", err)
+		return result
+	}
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	method := strings.ToUpper(check.Options["method"])
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var reqBody io.Reader
+	if body := check.Options["body"]; body != "" {
+		reqBody = strings.NewReader(body)
 	}
 
 	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		result.Success = false
+		result.Level = models.LevelCritical
 		result.Message = fmt.Sprintf("Failed to create request: %v", err)
 		result.Duration = time.Since(start)
 		return result
 	}
 
-	// Set User-Agent header
-	req.Header.Set("User-Agent", "HealthChecker/1.0")
+	// Set headers, custom headers may override the User-Agent
+	req.Header.Set("User-Agent", defaultUserAgent)
+	for key, value := range check.Options {
+		if name, ok := strings.CutPrefix(key, requestHeaderOptionPrefix); ok {
+			if strings.EqualFold(name, "Host") {
+				req.Host = value
+				continue
+			}
+			req.Header.Set(name, value)
+		}
+	}
+
+	// Set authentication
+	if user := check.Options["basic_auth_user"]; user != "" {
+		req.SetBasicAuth(user, check.Options["basic_auth_password"])
+	}
+	if token := check.Options["bearer_token"]; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
 	// Perform the request
 	resp, err := client.Do(req)
 	result.Duration = time.Since(start)
 
 	if err != nil {
-		result.Success = false
+		result.Level = models.LevelCritical
 		result.Message = fmt.Sprintf("HTTP request failed: %v", err)
 		return result
 	}
 	defer resp.Body.Close()
 
 	// Check status code
-	if resp.StatusCode == expectedStatus {
-		result.Success = true
-		result.Message = fmt.Sprintf("HTTP %d OK (response time: %v)", resp.StatusCode, result.Duration)
-	} else {
-		result.Success = false
+	if resp.StatusCode != expectedStatus {
+		result.Level = models.LevelCritical
 		result.Message = fmt.Sprintf("HTTP %d (expected %d)", resp.StatusCode, expectedStatus)
+		return result
+	}
+
+	// Only read the body when an assertion needs it
+	var body []byte
+	if needsBody(check.Options) {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxAssertBodySize))
+		result.Duration = time.Since(start)
+		if err != nil {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Failed to read response body: %v", err)
+			return result
+		}
+	}
+
+	if failures := assertResponse(check.Options, resp, body, result.Duration); len(failures) > 0 {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, strings.Join(failures, "; "))
+		return result
 	}
 
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("HTTP %d OK (response time: %v)", resp.StatusCode, result.Duration)
 	return result
 }
@@ -0,0 +1,193 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option key prefixes for assertions that can be repeated
+const (
+	jsonPathOptionPrefix = "json_path."
+	headerOptionPrefix   = "header."
+)
+
+// maxAssertBodySize is the maximum number of response body bytes inspected by assertions
+const maxAssertBodySize = 1 << 20
+
+// needsBody reports whether any of the configured assertions inspect the response body
+func needsBody(options map[string]string) bool {
+	for key := range options {
+		switch {
+		case key == "body_contains", key == "body_regex",
+			key == "body_not_contains", key == "body_not_regex",
+			strings.HasPrefix(key, jsonPathOptionPrefix):
+			return true
+		}
+	}
+	return false
+}
+
+// assertResponse evaluates the response assertions configured in options and
+// returns a description of every assertion that failed.
+//
+// Supported options:
+//   - body_contains / body_not_contains: substring the body must (not) contain
+//   - body_regex / body_not_regex: regular expression the body must (not) match
+//   - json_path.<path>: the value at <path> in the JSON body must equal the option value
+//   - header.<name>: the response header must be present and contain the option value
+//   - max_response_time: fail when the response took longer than this duration
+func assertResponse(options map[string]string, resp *http.Response, body []byte, elapsed time.Duration) []string {
+	var failures []string
+
+	if v := options["body_contains"]; v != "" && !strings.Contains(string(body), v) {
+		failures = append(failures, fmt.Sprintf("body does not contain %q", v))
+	}
+	if v := options["body_not_contains"]; v != "" && strings.Contains(string(body), v) {
+		failures = append(failures, fmt.Sprintf("body contains %q", v))
+	}
+	if v := options["body_regex"]; v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid body_regex: %v", err))
+		} else if !re.Match(body) {
+			failures = append(failures, fmt.Sprintf("body does not match %q", v))
+		}
+	}
+	if v := options["body_not_regex"]; v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid body_not_regex: %v", err))
+		} else if re.Match(body) {
+			failures = append(failures, fmt.Sprintf("body matches %q", v))
+		}
+	}
+
+	// Evaluate repeated assertions in a stable order so messages are deterministic
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var doc interface{}
+	var docErr error
+	parsed := false
+	for _, key := range keys {
+		if !strings.HasPrefix(key, jsonPathOptionPrefix) {
+			continue
+		}
+		if !parsed {
+			docErr = json.Unmarshal(body, &doc)
+			parsed = true
+		}
+		if docErr != nil {
+			failures = append(failures, fmt.Sprintf("body is not valid JSON: %v", docErr))
+			break
+		}
+
+		path := strings.TrimPrefix(key, jsonPathOptionPrefix)
+		want := options[key]
+		value, err := lookupJSONPath(doc, path)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("JSON path %s: %v", path, err))
+			continue
+		}
+		if got := jsonValueString(value); got != want {
+			failures = append(failures, fmt.Sprintf("JSON path %s is %q (expected %q)", path, got, want))
+		}
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, headerOptionPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, headerOptionPrefix)
+		want := options[key]
+		values, ok := resp.Header[http.CanonicalHeaderKey(name)]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("missing header %s", name))
+			continue
+		}
+		if want != "" && !strings.Contains(strings.Join(values, ", "), want) {
+			failures = append(failures, fmt.Sprintf("header %s is %q (expected %q)", name, strings.Join(values, ", "), want))
+		}
+	}
+
+	if v := options["max_response_time"]; v != "" {
+		limit, err := time.ParseDuration(v)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid max_response_time: %s", v))
+		} else if elapsed > limit {
+			failures = append(failures, fmt.Sprintf("response time %v exceeds %v", elapsed.Round(time.Millisecond), limit))
+		}
+	}
+
+	return failures
+}
+
+// lookupJSONPath resolves a dotted path such as "$.checks[0].status" in a decoded JSON document
+func lookupJSONPath(doc interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc, nil
+	}
+
+	current := doc
+	for _, segment := range strings.Split(path, ".") {
+		name := segment
+		var indexes []int
+		if i := strings.Index(segment, "["); i >= 0 {
+			name = segment[:i]
+			for _, part := range strings.Split(segment[i:], "[")[1:] {
+				idx, err := strconv.Atoi(strings.TrimSuffix(part, "]"))
+				if err != nil || !strings.HasSuffix(part, "]") {
+					return nil, fmt.Errorf("invalid index in %q", segment)
+				}
+				indexes = append(indexes, idx)
+			}
+		}
+
+		if name != "" {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%q is not an object", name)
+			}
+			current, ok = obj[name]
+			if !ok {
+				return nil, fmt.Errorf("field %q not found", name)
+			}
+		}
+
+		for _, idx := range indexes {
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%q is not an array", segment)
+			}
+			if idx < 0 || idx >= len(arr) {
+				return nil, fmt.Errorf("index %d out of range in %q", idx, segment)
+			}
+			current = arr[idx]
+		}
+	}
+
+	return current, nil
+}
+
+// jsonValueString formats a decoded JSON value for comparison with an option value
+func jsonValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
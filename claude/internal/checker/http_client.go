@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultMaxRedirects matches the redirect limit of the net/http default client
+const defaultMaxRedirects = 10
+
+// httpClientOptions are the check options that affect how the HTTP client is built
+var httpClientOptions = []string{
+	"follow_redirects",
+	"max_redirects",
+	"tls_skip_verify",
+	"ca_file",
+	"client_cert",
+	"client_key",
+	"proxy",
+}
+
+// clientFor returns a cached HTTP client for the client related options of a check,
+// building a new one the first time a combination of options is seen
+func (h *HTTPChecker) clientFor(options map[string]string) (*http.Client, error) {
+	parts := make([]string, 0, len(httpClientOptions))
+	for _, key := range httpClientOptions {
+		parts = append(parts, key+"="+options[key])
+	}
+	key := strings.Join(parts, "\x00")
+
+	h.clientsMux.Lock()
+	defer h.clientsMux.Unlock()
+
+	if client, ok := h.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := newHTTPClient(options)
+	if err != nil {
+		return nil, err
+	}
+	h.clients[key] = client
+	return client, nil
+}
+
+// newHTTPClient builds an HTTP client from the redirect, TLS and proxy options of a check.
+// Timeouts are applied per request through the request context.
+func newHTTPClient(options map[string]string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options["tls_skip_verify"] == "true",
+	}
+	if caFile := options["ca_file"]; caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	certFile, keyFile := options["client_cert"], options["client_key"]
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("client_cert and client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if proxy := options["proxy"]; proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("Invalid proxy URL: %s", proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	maxRedirects, err := intOption(options, "max_redirects", defaultMaxRedirects)
+	if err != nil {
+		return nil, err
+	}
+	followRedirects := options["follow_redirects"] != "false"
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !followRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}, nil
+}
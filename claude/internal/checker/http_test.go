@@ -0,0 +1,401 @@
+package checker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func newHTTPTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Service", "billing")
+		w.Write([]byte(`{"status":"degraded","version":2,"checks":[{"name":"db","ok":true},{"name":"cache","ok":false}]}`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("ok"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPCheckerAssertions(t *testing.T) {
+	server := newHTTPTestServer(t)
+
+	tests := []struct {
+		name        string
+		path        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:      "status only",
+			path:      "/health",
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "unexpected status",
+			path:        "/missing",
+			wantLevel:   models.LevelCritical,
+			wantMessage: "HTTP 404 (expected 200)",
+		},
+		{
+			name:      "body contains",
+			path:      "/health",
+			options:   map[string]string{"body_contains": `"status"`},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "body regex mismatch",
+			path:        "/health",
+			options:     map[string]string{"body_regex": `"status":"ok"`},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "body does not match",
+		},
+		{
+			name:        "negative body match",
+			path:        "/health",
+			options:     map[string]string{"body_not_contains": "degraded"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: `body contains "degraded"`,
+		},
+		{
+			name:        "JSON path mismatch",
+			path:        "/health",
+			options:     map[string]string{"json_path.status": "ok"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: `JSON path status is "degraded" (expected "ok")`,
+		},
+		{
+			name: "JSON paths match",
+			path: "/health",
+			options: map[string]string{
+				"json_path.$.version":      "2",
+				"json_path.checks[0].name": "db",
+				"json_path.$.checks[1].ok": "false",
+				"json_path.checks[0].ok":   "true",
+				"json_path.$.status":       "degraded",
+				"header.content-type":      "application/json",
+				"header.X-Service":         "",
+				"max_response_time":        "5s",
+				"body_not_regex":           `"status":"down"`,
+			},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "JSON path not found",
+			path:        "/health",
+			options:     map[string]string{"json_path.checks[5].name": "db"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "out of range",
+		},
+		{
+			name:        "body is not JSON",
+			path:        "/slow",
+			options:     map[string]string{"json_path.status": "ok"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "not valid JSON",
+		},
+		{
+			name:        "missing header",
+			path:        "/health",
+			options:     map[string]string{"header.X-Request-Id": ""},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "missing header X-Request-Id",
+		},
+		{
+			name:        "header value mismatch",
+			path:        "/health",
+			options:     map[string]string{"header.X-Service": "payments"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "header X-Service",
+		},
+		{
+			name:        "latency budget exceeded",
+			path:        "/slow",
+			options:     map[string]string{"max_response_time": "10ms"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "exceeds 10ms",
+		},
+		{
+			name:        "multiple failures reported",
+			path:        "/health",
+			options:     map[string]string{"json_path.status": "ok", "header.X-Request-Id": ""},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "; missing header",
+		},
+	}
+
+	checker := NewHTTPChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"url": server.URL + tt.path}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeHTTP,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "web"}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerRequestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			body, _ := io.ReadAll(r.Body)
+			user, pass, _ := r.BasicAuth()
+			fmt.Fprintf(w, "method=%s body=%s api-key=%s ua=%s host=%s basic=%s:%s auth=%s",
+				r.Method, body, r.Header.Get("X-Api-Key"), r.UserAgent(), r.Host, user, pass, r.Header.Get("Authorization"))
+		case "/redirect":
+			http.Redirect(w, r, "/redirect", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/echo", http.StatusMovedPermanently)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name: "method, body and headers",
+			path: "/echo",
+			options: map[string]string{
+				"method":                    "post",
+				"body":                      `{"probe":true}`,
+				"request_header.X-Api-Key":  "secret",
+				"request_header.User-Agent": "probe/2.0",
+				"request_header.Host":       "api.internal",
+				"body_contains":             `method=POST body={"probe":true} api-key=secret ua=probe/2.0 host=api.internal`,
+			},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "basic auth",
+			path:      "/echo",
+			options:   map[string]string{"basic_auth_user": "monitor", "basic_auth_password": "hunter2", "body_contains": "basic=monitor:hunter2"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "bearer token",
+			path:      "/echo",
+			options:   map[string]string{"bearer_token": "abc123", "body_contains": "auth=Bearer abc123"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "redirect followed",
+			path:      "/moved",
+			options:   map[string]string{"body_contains": "method=GET"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "redirect not followed",
+			path:      "/moved",
+			options:   map[string]string{"follow_redirects": "false", "expected_status": "301"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "redirect limit",
+			path:        "/redirect",
+			options:     map[string]string{"max_redirects": "3"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "stopped after 3 redirects",
+		},
+		{
+			name:        "invalid proxy",
+			path:        "/echo",
+			options:     map[string]string{"proxy": "://bad"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Invalid proxy URL",
+		},
+	}
+
+	checker := NewHTTPChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"url": server.URL + tt.path}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeHTTP,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "web"}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	check := models.Check{
+		Type:    models.CheckTypeHTTP,
+		Enabled: true,
+		Timeout: models.Duration(2 * time.Second),
+		Options: map[string]string{"url": "http://internal.test/health", "proxy": proxy.URL},
+	}
+	result := NewHTTPChecker().Check(context.Background(), models.Host{Name: "web"}, check)
+	if result.Level != models.LevelOK {
+		t.Fatalf("Expected proxied check to succeed: %s", result.Message)
+	}
+	if proxied != "http://internal.test/health" {
+		t.Errorf("Expected request to go through proxy, proxy saw %q", proxied)
+	}
+}
+
+func TestHTTPCheckerMutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "client=%s", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	certFile, keyFile := writeClientCert(t, dir, "monitor")
+
+	tests := []struct {
+		name      string
+		options   map[string]string
+		wantLevel models.Level
+	}{
+		{
+			name:      "client certificate",
+			options:   map[string]string{"ca_file": caFile, "client_cert": certFile, "client_key": keyFile, "body_contains": "client=monitor"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "skip verify with client certificate",
+			options:   map[string]string{"tls_skip_verify": "true", "client_cert": certFile, "client_key": keyFile},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "missing client certificate",
+			options:   map[string]string{"ca_file": caFile},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "unknown server certificate",
+			options:   map[string]string{"client_cert": certFile, "client_key": keyFile},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "client key without certificate",
+			options:   map[string]string{"client_key": keyFile},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewHTTPChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"url": server.URL}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeHTTP,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "web"}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+		})
+	}
+}
+
+// writeClientCert writes a self-signed client certificate and key to dir
+func writeClientCert(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("Failed to write client certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write client key: %v", err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// MySQLChecker implements MySQL health checks by logging in and running a query
+type MySQLChecker struct{}
+
+// NewMySQLChecker creates a new MySQL checker
+func NewMySQLChecker() *MySQLChecker {
+	return &MySQLChecker{}
+}
+
+// Type returns the checker type
+func (m *MySQLChecker) Type() models.CheckType {
+	return models.CheckTypeMySQL
+}
+
+// Check connects to the MySQL server, runs the configured query and optionally
+// compares the first column of the first row with an expected value.
+//
+// Supported options:
+//   - port: MySQL port (default: 3306)
+//   - user / password: login credentials (default user: root)
+//   - database: database to select
+//   - tls: "true", "skip-verify" or "preferred" to connect with TLS (default: false)
+//   - query: query to run (default: SELECT 1)
+//   - expect: expected value of the first column
+func (m *MySQLChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeMySQL,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	port := check.Options["port"]
+	if port == "" {
+		port = "3306"
+	}
+	query := check.Options["query"]
+	if query == "" {
+		query = defaultSQLQuery
+	}
+
+	config := mysql.NewConfig()
+	config.Net = "tcp"
+	config.Addr = net.JoinHostPort(host.Address, port)
+	config.User = check.Options["user"]
+	if config.User == "" {
+		config.User = "root"
+	}
+	config.Passwd = check.Options["password"]
+	config.DBName = check.Options["database"]
+	config.TLSConfig = check.Options["tls"]
+	config.Timeout = time.Duration(check.Timeout)
+	config.ReadTimeout = time.Duration(check.Timeout)
+	config.WriteTimeout = time.Duration(check.Timeout)
+	config.Logger = &mysql.NopLogger{}
+
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Invalid connection options: %v", err)
+		return result
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	start := time.Now()
+	var value sql.NullString
+	err = db.QueryRowContext(ctx, query).Scan(&value)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Query failed: %v", err)
+		return result
+	}
+
+	scalar := "NULL"
+	if value.Valid {
+		scalar = value.String
+	}
+	if failure := checkScalar(check.Options, scalar); failure != "" {
+		result.Level = models.LevelCritical
+		result.Message = failure
+		return result
+	}
+
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("Query returned %s (response time: %v)", scalar, result.Duration)
+	return result
+}
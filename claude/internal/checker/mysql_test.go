@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// mysqlSalt is the fixed auth-plugin-data sent in the stand-in server handshake
+var mysqlSalt = []byte("abcdefghijklmnopqrst")
+
+// startMySQLServer starts a minimal MySQL server speaking the client/server protocol.
+// It accepts the password "secret" using mysql_native_password and answers SELECT 1.
+func startMySQLServer(t *testing.T) (string, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveMySQL(conn)
+		}
+	}()
+
+	addr, port, _ := net.SplitHostPort(ln.Addr().String())
+	return addr, port
+}
+
+func serveMySQL(conn net.Conn) {
+	defer conn.Close()
+
+	// Capabilities: LONG_PASSWORD, CONNECT_WITH_DB, PROTOCOL_41, TRANSACTIONS,
+	// SECURE_CONNECTION and PLUGIN_AUTH
+	const capabilities = 0x1 | 0x8 | 0x200 | 0x2000 | 0x8000 | 0x80000
+
+	var handshake bytes.Buffer
+	handshake.WriteByte(10)
+	handshake.WriteString("8.0.36\x00")
+	binary.Write(&handshake, binary.LittleEndian, uint32(1))
+	handshake.Write(mysqlSalt[:8])
+	handshake.WriteByte(0)
+	binary.Write(&handshake, binary.LittleEndian, uint16(capabilities&0xffff))
+	handshake.WriteByte(0x21)
+	binary.Write(&handshake, binary.LittleEndian, uint16(2))
+	binary.Write(&handshake, binary.LittleEndian, uint16(capabilities>>16))
+	handshake.WriteByte(byte(len(mysqlSalt) + 1))
+	handshake.Write(make([]byte, 10))
+	handshake.Write(mysqlSalt[8:])
+	handshake.WriteByte(0)
+	handshake.WriteString("mysql_native_password\x00")
+	writeMySQLPacket(conn, 0, handshake.Bytes())
+
+	seq, response, err := readMySQLPacket(conn)
+	if err != nil || len(response) < 33 {
+		return
+	}
+	rest := response[32:]
+	user, rest, _ := bytes.Cut(rest, []byte{0})
+	authLen := int(rest[0])
+	auth := rest[1 : 1+authLen]
+
+	if !bytes.Equal(auth, mysqlNativePassword("secret", mysqlSalt)) {
+		writeMySQLPacket(conn, seq+1, mysqlError(1045, "28000", "Access denied for user '"+string(user)+"'@'localhost' (using password: YES)"))
+		return
+	}
+	writeMySQLPacket(conn, seq+1, []byte{0x00, 0, 0, 2, 0, 0, 0})
+
+	for {
+		_, command, err := readMySQLPacket(conn)
+		if err != nil || len(command) == 0 || command[0] != 0x03 {
+			return
+		}
+
+		if string(command[1:]) != "SELECT 1" {
+			writeMySQLPacket(conn, 1, mysqlError(1064, "42000", "You have an error in your SQL syntax"))
+			continue
+		}
+
+		var column bytes.Buffer
+		for _, s := range []string{"def", "", "", "", "1", ""} {
+			column.WriteByte(byte(len(s)))
+			column.WriteString(s)
+		}
+		column.Write([]byte{0x0c, 0x3f, 0x00, 1, 0, 0, 0, 0x08, 0x81, 0x00, 0x00, 0x00, 0x00})
+		eof := []byte{0xfe, 0, 0, 2, 0}
+
+		writeMySQLPacket(conn, 1, []byte{1})
+		writeMySQLPacket(conn, 2, column.Bytes())
+		writeMySQLPacket(conn, 3, eof)
+		writeMySQLPacket(conn, 4, []byte{1, '1'})
+		writeMySQLPacket(conn, 5, eof)
+	}
+}
+
+func readMySQLPacket(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	_, err := io.ReadFull(r, payload)
+	return header[3], payload, err
+}
+
+func writeMySQLPacket(w io.Writer, seq byte, payload []byte) {
+	n := len(payload)
+	w.Write(append([]byte{byte(n), byte(n >> 8), byte(n >> 16), seq}, payload...))
+}
+
+func mysqlError(code uint16, state, message string) []byte {
+	packet := []byte{0xff, byte(code), byte(code >> 8), '#'}
+	return append(append(packet, state...), message...)
+}
+
+// mysqlNativePassword computes SHA1(password) XOR SHA1(salt + SHA1(SHA1(password)))
+func mysqlNativePassword(password string, salt []byte) []byte {
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	scramble := sha1.Sum(append(append([]byte{}, salt...), stage2[:]...))
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble[:]
+}
+
+func TestMySQLChecker(t *testing.T) {
+	addr, port := startMySQLServer(t)
+
+	tests := []struct {
+		name        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "default query",
+			options:     map[string]string{"user": "monitor", "password": "secret"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "Query returned 1",
+		},
+		{
+			name:      "expected scalar",
+			options:   map[string]string{"user": "monitor", "password": "secret", "query": "SELECT 1", "expect": "1"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "unexpected scalar",
+			options:     map[string]string{"user": "monitor", "password": "secret", "expect": "2"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: `Query returned "1" (expected "2")`,
+		},
+		{
+			name:        "query error",
+			options:     map[string]string{"user": "monitor", "password": "secret", "query": "SELEC 1"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "SQL syntax",
+		},
+		{
+			name:        "login rejected",
+			options:     map[string]string{"user": "monitor", "password": "wrong"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Access denied for user 'monitor'",
+		},
+		{
+			name:        "invalid tls option",
+			options:     map[string]string{"tls": "sometimes"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Invalid connection options",
+		},
+	}
+
+	checker := NewMySQLChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"port": port}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeMySQL,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "db", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -3,12 +3,22 @@ package checker
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
 	"github.com/go-ping/ping"
 )
 
+// Ping defaults, chosen so a single lost packet does not fail the check
+const (
+	defaultPingCount    = 3
+	defaultPingInterval = 200 * time.Millisecond
+	defaultPingMaxLoss  = 50.0
+)
+
 // PingChecker implements ICMP ping health checks
 type PingChecker struct{}
 
@@ -22,7 +32,29 @@ func (p *PingChecker) Type() models.CheckType {
 	return models.CheckTypePing
 }
 
-// Check performs a ping check on the host
+// pingThresholds are the limits a ping run must stay within to pass, and
+// optionally the lower limits above which it passes with a warning
+type pingThresholds struct {
+	maxLoss    float64
+	maxRtt     time.Duration
+	maxJitter  time.Duration
+	warnLoss   float64 // Negative when not set
+	warnRtt    time.Duration
+	warnJitter time.Duration
+}
+
+// Check performs a ping check on the host and reports packet loss, RTT and
+// jitter statistics as metrics.
+//
+// Supported options:
+//   - count: number of packets to send (default: 3)
+//   - interval: time between packets (default: 200ms)
+//   - size: packet payload size in bytes (default: 24)
+//   - privileged: "true" to use raw ICMP sockets instead of unprivileged UDP pings
+//   - max_loss: maximum packet loss percentage (default: 50)
+//   - max_rtt: maximum average round-trip time, e.g. "100ms"
+//   - max_jitter: maximum jitter (mean difference between consecutive RTTs)
+//   - warn_loss, warn_rtt, warn_jitter: report WARNING above these, below the maximums
 func (p *PingChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
 	result := models.CheckResult{
 		Host:      host.Name,
@@ -31,60 +63,218 @@ func (p *PingChecker) Check(ctx context.Context, host models.Host, check models.
 	}
 
 	if !check.Enabled {
-		result.Success = true
+		result.Level = models.LevelOK
 		result.Message = "Check disabled"
 		return result
 	}
 
 	start := time.Now()
 
+	count, err := intOption(check.Options, "count", defaultPingCount)
+	if err == nil && count < 1 {
+		err = fmt.Errorf("Invalid count: %d", count)
+	}
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	size, err := intOption(check.Options, "size", 0)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	interval, err := durationOption(check.Options, "interval", defaultPingInterval)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	thresholds, err := parsePingThresholds(check.Options)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+
 	pinger, err := ping.NewPinger(host.Address)
 	if err != nil {
-		result.Success = false
+		result.Level = models.LevelCritical
 		result.Message = fmt.Sprintf("Failed to create pinger: %v", err)
 		result.Duration = time.Since(start)
 		return result
 	}
 
-	// Set privileged mode to false to use UDP instead of ICMP (works without root)
-	pinger.SetPrivileged(false)
-	pinger.Count = 1
+	// Unprivileged mode uses UDP instead of raw ICMP sockets (works without root)
+	pinger.SetPrivileged(check.Options["privileged"] == "true")
+	pinger.Count = count
+	pinger.Interval = interval
 	pinger.Timeout = time.Duration(check.Timeout)
+	if size > 0 {
+		pinger.Size = size
+	}
 
-	// Run ping with context
-	done := make(chan bool)
+	// Run ping with context. The channel is buffered so the goroutine can
+	// finish after a cancelled check has returned.
+	done := make(chan error, 1)
 	go func() {
-		err = pinger.Run()
-		done <- true
+		done <- pinger.Run()
 	}()
 
 	select {
 	case <-ctx.Done():
 		pinger.Stop()
-		result.Success = false
+		result.Level = models.LevelCritical
 		result.Message = "Check cancelled"
 		result.Duration = time.Since(start)
 		return result
-	case <-done:
-		// Continue processing
+	case err = <-done:
 	}
 
 	result.Duration = time.Since(start)
 
 	if err != nil {
-		result.Success = false
+		result.Level = models.LevelCritical
 		result.Message = fmt.Sprintf("Ping failed: %v", err)
 		return result
 	}
 
 	stats := pinger.Statistics()
 	if stats.PacketsRecv > 0 {
-		result.Success = true
-		result.Message = fmt.Sprintf("Ping successful (rtt: %v)", stats.AvgRtt)
-	} else {
-		result.Success = false
-		result.Message = "No packets received"
+		// Report the average RTT as the check latency
+		result.Duration = stats.AvgRtt
 	}
+	result.Metrics = pingMetrics(stats)
+	result.Level, result.Message = evaluatePing(stats, thresholds)
 
 	return result
 }
+
+// parsePingThresholds reads the loss, RTT and jitter limits from the check options
+func parsePingThresholds(options map[string]string) (pingThresholds, error) {
+	thresholds := pingThresholds{maxLoss: defaultPingMaxLoss, warnLoss: -1}
+
+	var err error
+	if thresholds.maxLoss, err = lossOption(options, "max_loss", defaultPingMaxLoss); err != nil {
+		return thresholds, err
+	}
+	if thresholds.warnLoss, err = lossOption(options, "warn_loss", -1); err != nil {
+		return thresholds, err
+	}
+	if thresholds.maxRtt, err = durationOption(options, "max_rtt", 0); err != nil {
+		return thresholds, err
+	}
+	if thresholds.maxJitter, err = durationOption(options, "max_jitter", 0); err != nil {
+		return thresholds, err
+	}
+	if thresholds.warnRtt, err = durationOption(options, "warn_rtt", 0); err != nil {
+		return thresholds, err
+	}
+	if thresholds.warnJitter, err = durationOption(options, "warn_jitter", 0); err != nil {
+		return thresholds, err
+	}
+	return thresholds, nil
+}
+
+// lossOption reads a packet loss percentage such as "20%" from the check options
+func lossOption(options map[string]string, key string, def float64) (float64, error) {
+	v := strings.TrimSuffix(options[key], "%")
+	if v == "" {
+		return def, nil
+	}
+	loss, err := strconv.ParseFloat(v, 64)
+	if err != nil || loss < 0 || loss > 100 {
+		return 0, fmt.Errorf("Invalid %s: %s", key, options[key])
+	}
+	return loss, nil
+}
+
+// evaluatePing applies the thresholds to the ping statistics and returns the
+// level and a summary message
+func evaluatePing(stats *ping.Statistics, thresholds pingThresholds) (models.Level, string) {
+	summary := fmt.Sprintf("%d/%d packets received, %.0f%% loss", stats.PacketsRecv, stats.PacketsSent, stats.PacketLoss)
+
+	if stats.PacketsRecv == 0 {
+		return models.LevelCritical, "No packets received (" + summary + ")"
+	}
+
+	jitter := pingJitter(stats.Rtts)
+	summary += fmt.Sprintf(", rtt min/avg/max/stddev = %v/%v/%v/%v, jitter %v",
+		roundRtt(stats.MinRtt), roundRtt(stats.AvgRtt), roundRtt(stats.MaxRtt), roundRtt(stats.StdDevRtt), roundRtt(jitter))
+
+	var failures []string
+	if stats.PacketLoss > thresholds.maxLoss {
+		failures = append(failures, fmt.Sprintf("packet loss %.0f%% exceeds %.0f%%", stats.PacketLoss, thresholds.maxLoss))
+	}
+	if thresholds.maxRtt > 0 && stats.AvgRtt > thresholds.maxRtt {
+		failures = append(failures, fmt.Sprintf("average rtt %v exceeds %v", roundRtt(stats.AvgRtt), thresholds.maxRtt))
+	}
+	if thresholds.maxJitter > 0 && jitter > thresholds.maxJitter {
+		failures = append(failures, fmt.Sprintf("jitter %v exceeds %v", roundRtt(jitter), thresholds.maxJitter))
+	}
+
+	if len(failures) > 0 {
+		return models.LevelCritical, fmt.Sprintf("%s (%s)", strings.Join(failures, "; "), summary)
+	}
+
+	var warnings []string
+	if thresholds.warnLoss >= 0 && stats.PacketLoss > thresholds.warnLoss {
+		warnings = append(warnings, fmt.Sprintf("packet loss %.0f%% exceeds %.0f%%", stats.PacketLoss, thresholds.warnLoss))
+	}
+	if thresholds.warnRtt > 0 && stats.AvgRtt > thresholds.warnRtt {
+		warnings = append(warnings, fmt.Sprintf("average rtt %v exceeds %v", roundRtt(stats.AvgRtt), thresholds.warnRtt))
+	}
+	if thresholds.warnJitter > 0 && jitter > thresholds.warnJitter {
+		warnings = append(warnings, fmt.Sprintf("jitter %v exceeds %v", roundRtt(jitter), thresholds.warnJitter))
+	}
+
+	if len(warnings) > 0 {
+		return models.LevelWarning, fmt.Sprintf("Ping degraded, %s (%s)", strings.Join(warnings, "; "), summary)
+	}
+	return models.LevelOK, "Ping successful (" + summary + ")"
+}
+
+// pingJitter returns the mean absolute difference between consecutive round-trip times
+func pingJitter(rtts []time.Duration) time.Duration {
+	if len(rtts) < 2 {
+		return 0
+	}
+	var total time.Duration
+	for i := 1; i < len(rtts); i++ {
+		diff := rtts[i] - rtts[i-1]
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+	}
+	return total / time.Duration(len(rtts)-1)
+}
+
+// pingMetrics converts ping statistics into metrics, with times in milliseconds
+func pingMetrics(stats *ping.Statistics) []models.Metric {
+	ms := func(d time.Duration) float64 {
+		return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+	}
+
+	metrics := []models.Metric{
+		{Label: "sent", Value: float64(stats.PacketsSent)},
+		{Label: "received", Value: float64(stats.PacketsRecv)},
+		{Label: "loss", Value: stats.PacketLoss, Unit: "%"},
+	}
+	if stats.PacketsRecv > 0 {
+		metrics = append(metrics,
+			models.Metric{Label: "rtt_min", Value: ms(stats.MinRtt), Unit: "ms"},
+			models.Metric{Label: "rtt_avg", Value: ms(stats.AvgRtt), Unit: "ms"},
+			models.Metric{Label: "rtt_max", Value: ms(stats.MaxRtt), Unit: "ms"},
+			models.Metric{Label: "rtt_stddev", Value: ms(stats.StdDevRtt), Unit: "ms"},
+			models.Metric{Label: "jitter", Value: ms(pingJitter(stats.Rtts)), Unit: "ms"},
+		)
+	}
+	return metrics
+}
+
+// roundRtt rounds round-trip times for display
+func roundRtt(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,160 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+	"github.com/go-ping/ping"
+)
+
+func TestEvaluatePing(t *testing.T) {
+	ms := time.Millisecond
+	stats := func(sent, recv int, rtts ...time.Duration) *ping.Statistics {
+		s := &ping.Statistics{PacketsSent: sent, PacketsRecv: recv, Rtts: rtts}
+		if sent > 0 {
+			s.PacketLoss = float64(sent-recv) / float64(sent) * 100
+		}
+		if len(rtts) > 0 {
+			s.MinRtt, s.MaxRtt = rtts[0], rtts[0]
+			var total time.Duration
+			for _, rtt := range rtts {
+				s.MinRtt = min(s.MinRtt, rtt)
+				s.MaxRtt = max(s.MaxRtt, rtt)
+				total += rtt
+			}
+			s.AvgRtt = total / time.Duration(len(rtts))
+		}
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		stats       *ping.Statistics
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "all replies",
+			stats:       stats(3, 3, 10*ms, 11*ms, 12*ms),
+			wantLevel:   models.LevelOK,
+			wantMessage: "3/3 packets received, 0% loss",
+		},
+		{
+			name:      "single lost packet tolerated",
+			stats:     stats(3, 2, 10*ms, 12*ms),
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "heavy loss",
+			stats:       stats(5, 3, 10*ms, 10*ms, 10*ms),
+			options:     map[string]string{"max_loss": "20%"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "packet loss 40% exceeds 20%",
+		},
+		{
+			name:        "no replies",
+			stats:       stats(3, 0),
+			wantLevel:   models.LevelCritical,
+			wantMessage: "No packets received",
+		},
+		{
+			name:        "average rtt threshold",
+			stats:       stats(3, 3, 90*ms, 100*ms, 110*ms),
+			options:     map[string]string{"max_rtt": "50ms"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "average rtt 100ms exceeds 50ms",
+		},
+		{
+			name:        "jitter threshold",
+			stats:       stats(3, 3, 10*ms, 50*ms, 10*ms),
+			options:     map[string]string{"max_jitter": "20ms"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "jitter 40ms exceeds 20ms",
+		},
+		{
+			name:        "warning loss",
+			stats:       stats(5, 4, 10*ms, 10*ms, 10*ms, 10*ms),
+			options:     map[string]string{"warn_loss": "10%"},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "Ping degraded, packet loss 20% exceeds 10%",
+		},
+		{
+			name:        "critical wins over warning",
+			stats:       stats(3, 3, 90*ms, 100*ms, 110*ms),
+			options:     map[string]string{"warn_rtt": "20ms", "max_rtt": "50ms"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "average rtt 100ms exceeds 50ms",
+		},
+		{
+			name:        "warning rtt",
+			stats:       stats(3, 3, 30*ms, 30*ms, 30*ms),
+			options:     map[string]string{"warn_rtt": "20ms", "max_rtt": "50ms"},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "average rtt 30ms exceeds 20ms",
+		},
+		{
+			name:      "within all thresholds",
+			stats:     stats(4, 4, 10*ms, 12*ms, 10*ms, 12*ms),
+			options:   map[string]string{"max_loss": "0", "max_rtt": "20ms", "max_jitter": "5ms"},
+			wantLevel: models.LevelOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds, err := parsePingThresholds(tt.options)
+			if err != nil {
+				t.Fatalf("parsePingThresholds() error = %v", err)
+			}
+			level, message := evaluatePing(tt.stats, thresholds)
+			if level != tt.wantLevel {
+				t.Errorf("evaluatePing() level = %v, want %v (message: %s)", level, tt.wantLevel, message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("evaluatePing() message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParsePingThresholdsInvalid(t *testing.T) {
+	for _, options := range []map[string]string{
+		{"max_loss": "lots"},
+		{"max_loss": "150"},
+		{"max_rtt": "fast"},
+		{"max_jitter": "-5ms"},
+	} {
+		if _, err := parsePingThresholds(options); err == nil {
+			t.Errorf("parsePingThresholds(%v) expected error", options)
+		}
+	}
+}
+
+func TestPingMetrics(t *testing.T) {
+	stats := &ping.Statistics{
+		PacketsSent: 4,
+		PacketsRecv: 3,
+		PacketLoss:  25,
+		Rtts:        []time.Duration{1500 * time.Microsecond, 2 * time.Millisecond, 1 * time.Millisecond},
+		MinRtt:      1 * time.Millisecond,
+		AvgRtt:      1500 * time.Microsecond,
+		MaxRtt:      2 * time.Millisecond,
+	}
+
+	got := make(map[string]float64)
+	for _, m := range pingMetrics(stats) {
+		got[m.Label] = m.Value
+	}
+	want := map[string]float64{
+		"sent": 4, "received": 3, "loss": 25,
+		"rtt_min": 1, "rtt_avg": 1.5, "rtt_max": 2, "rtt_stddev": 0, "jitter": 0.75,
+	}
+	for label, value := range want {
+		if got[label] != value {
+			t.Errorf("metric %s = %v, want %v", label, got[label], value)
+		}
+	}
+}
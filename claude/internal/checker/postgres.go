@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// PostgresChecker implements PostgreSQL health checks by logging in and running a query
+type PostgresChecker struct{}
+
+// NewPostgresChecker creates a new PostgreSQL checker
+func NewPostgresChecker() *PostgresChecker {
+	return &PostgresChecker{}
+}
+
+// Type returns the checker type
+func (p *PostgresChecker) Type() models.CheckType {
+	return models.CheckTypePostgres
+}
+
+// Check connects to the PostgreSQL server, runs the configured query and
+// optionally compares the first column of the first row with an expected value.
+// Rejected logins and servers that are starting up or shutting down fail the check.
+//
+// Supported options:
+//   - port: PostgreSQL port (default: 5432)
+//   - user / password: login credentials (default user: postgres)
+//   - database: database to connect to (default: the user name)
+//   - sslmode: disable, allow, prefer, require, verify-ca or verify-full (default: prefer)
+//   - query: query to run (default: SELECT 1)
+//   - expect: expected value of the first column, e.g. "false" for SELECT pg_is_in_recovery()
+func (p *PostgresChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypePostgres,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	port := check.Options["port"]
+	if port == "" {
+		port = "5432"
+	}
+	user := check.Options["user"]
+	if user == "" {
+		user = "postgres"
+	}
+	sslmode := check.Options["sslmode"]
+	if sslmode == "" {
+		sslmode = "prefer"
+	}
+	query := check.Options["query"]
+	if query == "" {
+		query = defaultSQLQuery
+	}
+
+	connURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, check.Options["password"]),
+		Host:     net.JoinHostPort(host.Address, port),
+		Path:     "/" + check.Options["database"],
+		RawQuery: url.Values{"sslmode": {sslmode}}.Encode(),
+	}
+	if check.Options["password"] == "" {
+		connURL.User = url.User(user)
+	}
+	config, err := pgx.ParseConfig(connURL.String())
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Invalid connection options: %v", err)
+		return result
+	}
+	config.ConnectTimeout = time.Duration(check.Timeout)
+	// The simple protocol avoids preparing a statement for a single query
+	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	start := time.Now()
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Failed to connect: %v", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer conn.Close(context.Background())
+
+	var value any
+	err = conn.QueryRow(ctx, query).Scan(&value)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Query failed: %v", err)
+		return result
+	}
+
+	scalar := "NULL"
+	if value != nil {
+		scalar = fmt.Sprint(value)
+	}
+	if failure := checkScalar(check.Options, scalar); failure != "" {
+		result.Level = models.LevelCritical
+		result.Message = failure
+		return result
+	}
+
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("Query returned %s (response time: %v)", scalar, result.Duration)
+	return result
+}
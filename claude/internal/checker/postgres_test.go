@@ -0,0 +1,178 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// startPostgresServer starts a minimal PostgreSQL server speaking the frontend/backend
+// protocol. It accepts the password "secret", reports it is starting up for the
+// user "starting" and answers a few fixed simple queries.
+func startPostgresServer(t *testing.T) (string, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go servePostgres(conn)
+		}
+	}()
+
+	addr, port, _ := net.SplitHostPort(ln.Addr().String())
+	return addr, port
+}
+
+func servePostgres(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+
+	msg, err := backend.ReceiveStartupMessage()
+	if _, ok := msg.(*pgproto3.SSLRequest); ok {
+		conn.Write([]byte("N"))
+		msg, err = backend.ReceiveStartupMessage()
+	}
+	startup, ok := msg.(*pgproto3.StartupMessage)
+	if err != nil || !ok {
+		return
+	}
+
+	if startup.Parameters["user"] == "starting" {
+		backend.Send(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "57P03", Message: "the database system is starting up"})
+		backend.Flush()
+		return
+	}
+
+	backend.Send(&pgproto3.AuthenticationCleartextPassword{})
+	backend.Flush()
+	backend.SetAuthType(pgproto3.AuthTypeCleartextPassword)
+	msg, err = backend.Receive()
+	if password, ok := msg.(*pgproto3.PasswordMessage); err != nil || !ok || password.Password != "secret" {
+		backend.Send(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "28P01", Message: "password authentication failed for user \"" + startup.Parameters["user"] + "\""})
+		backend.Flush()
+		return
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ParameterStatus{Name: "server_version", Value: "16.4"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	backend.Flush()
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			return
+		}
+
+		switch query.String {
+		case "SELECT 1":
+			backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{Name: []byte("?column?"), DataTypeOID: 23, DataTypeSize: 4}}})
+			backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte("1")}})
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+		case "SELECT pg_is_in_recovery()":
+			backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{Name: []byte("pg_is_in_recovery"), DataTypeOID: 16, DataTypeSize: 1}}})
+			backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte("t")}})
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+		default:
+			backend.Send(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "42601", Message: "syntax error"})
+		}
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		backend.Flush()
+	}
+}
+
+func TestPostgresChecker(t *testing.T) {
+	addr, port := startPostgresServer(t)
+
+	tests := []struct {
+		name        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "default query",
+			options:     map[string]string{"password": "secret"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "Query returned 1",
+		},
+		{
+			name:      "expected scalar",
+			options:   map[string]string{"password": "secret", "sslmode": "disable", "query": "SELECT 1", "expect": "1"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "server in recovery",
+			options:     map[string]string{"password": "secret", "query": "SELECT pg_is_in_recovery()", "expect": "false"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: `Query returned "true" (expected "false")`,
+		},
+		{
+			name:        "query error",
+			options:     map[string]string{"password": "secret", "query": "SELEC 1"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "syntax error",
+		},
+		{
+			name:        "login rejected",
+			options:     map[string]string{"user": "monitor", "password": "wrong"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "password authentication failed",
+		},
+		{
+			name:        "starting up",
+			options:     map[string]string{"user": "starting"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "starting up",
+		},
+		{
+			name:        "invalid sslmode",
+			options:     map[string]string{"sslmode": "sometimes"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Invalid connection options",
+		},
+	}
+
+	checker := NewPostgresChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"port": port}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypePostgres,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "db", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// RedisChecker implements Redis health checks using PING and INFO replication
+type RedisChecker struct{}
+
+// NewRedisChecker creates a new Redis checker
+func NewRedisChecker() *RedisChecker {
+	return &RedisChecker{}
+}
+
+// Type returns the checker type
+func (r *RedisChecker) Type() models.CheckType {
+	return models.CheckTypeRedis
+}
+
+// Check authenticates to the Redis server, sends PING and reads the replication
+// role. Replicas fail when their link to the master is down.
+//
+// Supported options:
+//   - port: Redis port (default: 6379)
+//   - username / password: credentials for AUTH
+//   - db: database number to select (default: 0)
+//   - tls: "true" to connect with TLS
+//   - tls_skip_verify: "true" to skip server certificate verification
+//   - role: expected replication role, "master" or "replica"
+func (r *RedisChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeRedis,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	db, err := intOption(check.Options, "db", 0)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	wantRole := normalizeRedisRole(check.Options["role"])
+	if wantRole != "" && wantRole != "master" && wantRole != "slave" {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Invalid role: %s", check.Options["role"])
+		return result
+	}
+
+	port := check.Options["port"]
+	if port == "" {
+		port = "6379"
+	}
+
+	opts := &redis.Options{
+		Addr:            net.JoinHostPort(host.Address, port),
+		Username:        check.Options["username"],
+		Password:        check.Options["password"],
+		DB:              db,
+		Protocol:        2,
+		DisableIdentity: true,
+		MaxRetries:      -1,
+		PoolSize:        1,
+		DialTimeout:     time.Duration(check.Timeout),
+		ReadTimeout:     time.Duration(check.Timeout),
+		WriteTimeout:    time.Duration(check.Timeout),
+	}
+	if check.Options["tls"] == "true" {
+		opts.TLSConfig = &tls.Config{
+			ServerName:         host.Address,
+			InsecureSkipVerify: check.Options["tls_skip_verify"] == "true",
+		}
+	}
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(check.Timeout))
+		defer cancel()
+	}
+
+	start := time.Now()
+	pong, err := client.Ping(ctx).Result()
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("PING failed: %v", err)
+		return result
+	}
+
+	info, err := client.Info(ctx, "replication").Result()
+	if err != nil {
+		// INFO may be denied by ACLs, which only matters when a role is expected
+		if wantRole != "" {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("INFO replication failed: %v", err)
+			return result
+		}
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("%s (response time: %v)", pong, result.Duration)
+		return result
+	}
+
+	replication := parseRedisInfo(info)
+	role := replication["role"]
+	if wantRole != "" && role != wantRole {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Role is %s (expected %s)", role, wantRole)
+		return result
+	}
+
+	if role == "slave" {
+		if link := replication["master_link_status"]; link != "up" {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Replica link to master %s:%s is %s",
+				replication["master_host"], replication["master_port"], link)
+			return result
+		}
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("%s (role: replica, master link up, response time: %v)", pong, result.Duration)
+		return result
+	}
+
+	result.Level = models.LevelOK
+	result.Message = fmt.Sprintf("%s (role: %s, connected replicas: %s, response time: %v)",
+		pong, role, replication["connected_slaves"], result.Duration)
+	return result
+}
+
+// normalizeRedisRole maps role names to the values reported by INFO replication
+func normalizeRedisRole(role string) string {
+	switch role = strings.ToLower(role); role {
+	case "primary":
+		return "master"
+	case "replica":
+		return "slave"
+	}
+	return role
+}
+
+// parseRedisInfo parses the key:value lines of an INFO reply
+func parseRedisInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,191 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// startRedisServer starts a minimal RESP server that requires the password
+// "secret" and answers INFO replication with the given section
+func startRedisServer(t *testing.T, replication string) (string, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveRedis(conn, replication)
+		}
+	}()
+
+	addr, port, _ := net.SplitHostPort(ln.Addr().String())
+	return addr, port
+}
+
+func serveRedis(conn net.Conn, replication string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := false
+
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			if args[len(args)-1] != "secret" {
+				fmt.Fprint(conn, "-WRONGPASS invalid username-password pair or user is disabled.\r\n")
+				continue
+			}
+			authenticated = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case !authenticated:
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+		case cmd == "PING":
+			fmt.Fprint(conn, "+PONG\r\n")
+		case cmd == "SELECT":
+			fmt.Fprint(conn, "+OK\r\n")
+		case cmd == "INFO":
+			fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(replication), replication)
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+// readRESPCommand reads a command sent as a RESP array of bulk strings
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid command header %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk header %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisChecker(t *testing.T) {
+	masterAddr, masterPort := startRedisServer(t, "# Replication\r\nrole:master\r\nconnected_slaves:2\r\n")
+	replicaAddr, replicaPort := startRedisServer(t, "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:up\r\n")
+	brokenAddr, brokenPort := startRedisServer(t, "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:down\r\n")
+
+	tests := []struct {
+		name        string
+		addr        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "master",
+			addr:        masterAddr,
+			options:     map[string]string{"port": masterPort, "password": "secret", "db": "2"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "PONG (role: master, connected replicas: 2",
+		},
+		{
+			name:      "expected role",
+			addr:      masterAddr,
+			options:   map[string]string{"port": masterPort, "password": "secret", "role": "primary"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "unexpected role",
+			addr:        replicaAddr,
+			options:     map[string]string{"port": replicaPort, "password": "secret", "role": "master"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Role is slave (expected master)",
+		},
+		{
+			name:        "healthy replica",
+			addr:        replicaAddr,
+			options:     map[string]string{"port": replicaPort, "password": "secret", "role": "replica"},
+			wantLevel:   models.LevelOK,
+			wantMessage: "master link up",
+		},
+		{
+			name:        "replica link down",
+			addr:        brokenAddr,
+			options:     map[string]string{"port": brokenPort, "password": "secret"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Replica link to master 10.0.0.1:6379 is down",
+		},
+		{
+			name:        "wrong password",
+			addr:        masterAddr,
+			options:     map[string]string{"port": masterPort, "password": "wrong"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "WRONGPASS",
+		},
+		{
+			name:        "no password",
+			addr:        masterAddr,
+			options:     map[string]string{"port": masterPort},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "NOAUTH",
+		},
+		{
+			name:        "invalid role",
+			addr:        masterAddr,
+			options:     map[string]string{"port": masterPort, "role": "leader"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Invalid role",
+		},
+	}
+
+	checker := NewRedisChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := models.Check{
+				Type:    models.CheckTypeRedis,
+				Enabled: true,
+				Timeout: models.Duration(2 * time.Second),
+				Options: tt.options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "cache", Address: tt.addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultSQLQuery is run by the database checkers when no query is configured
+const defaultSQLQuery = "SELECT 1"
+
+// checkScalar compares the scalar returned by a health query with the expect option.
+// It returns a failure description, or an empty string when the value is acceptable.
+func checkScalar(options map[string]string, value string) string {
+	want, ok := options["expect"]
+	if !ok {
+		return ""
+	}
+	if !strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(want)) {
+		return fmt.Sprintf("Query returned %q (expected %q)", value, want)
+	}
+	return ""
+}
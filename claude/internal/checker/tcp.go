@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// maxBannerSize is the maximum number of bytes read from the server when matching a reply
+const maxBannerSize = 4096
+
+// TCPChecker implements TCP port connect checks with an optional banner exchange
+type TCPChecker struct{}
+
+// NewTCPChecker creates a new TCP checker
+func NewTCPChecker() *TCPChecker {
+	return &TCPChecker{}
+}
+
+// Type returns the checker type
+func (t *TCPChecker) Type() models.CheckType {
+	return models.CheckTypeTCP
+}
+
+// Check connects to the configured port on the host and optionally sends a
+// payload and matches the reply.
+//
+// Supported options:
+//   - port: TCP port to connect to (required)
+//   - send: payload written after connecting
+//   - expect: substring the reply must contain
+//   - expect_regex: regular expression the reply must match
+func (t *TCPChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeTCP,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	start := time.Now()
+
+	port := check.Options["port"]
+	if port == "" {
+		result.Level = models.LevelCritical
+		result.Message = "No port configured"
+		return result
+	}
+
+	var re *regexp.Regexp
+	if pattern := check.Options["expect_regex"]; pattern != "" {
+		var err error
+		re, err = regexp.Compile(pattern)
+		if err != nil {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Invalid expect_regex: %v", err)
+			return result
+		}
+	}
+	expect := check.Options["expect"]
+	send := check.Options["send"]
+
+	address := net.JoinHostPort(host.Address, port)
+	dialer := &net.Dialer{Timeout: time.Duration(check.Timeout)}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	connectTime := time.Since(start)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("TCP connect to %s failed: %v", address, err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	// Bound the banner exchange by the check timeout and the context deadline
+	conn.SetDeadline(deadlineFor(ctx, start, time.Duration(check.Timeout)))
+
+	if send != "" {
+		if _, err := io.WriteString(conn, send); err != nil {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Failed to send payload to %s: %v", address, err)
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+
+	if expect == "" && re == nil {
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("TCP connect to %s OK (connect time: %v)", address, connectTime)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	matches := func(reply []byte) bool {
+		if expect != "" && !bytes.Contains(reply, []byte(expect)) {
+			return false
+		}
+		if re != nil && !re.Match(reply) {
+			return false
+		}
+		return true
+	}
+
+	reply, matched, err := readUntil(conn, matches)
+	result.Duration = time.Since(start)
+
+	if matched {
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("TCP reply from %s matched (response time: %v)", address, result.Duration)
+		return result
+	}
+
+	result.Level = models.LevelCritical
+	if len(reply) == 0 && err != nil {
+		result.Message = fmt.Sprintf("No reply from %s: %v", address, err)
+	} else {
+		result.Message = fmt.Sprintf("Unexpected reply from %s: %q", address, truncate(strings.TrimSpace(string(reply)), 80))
+	}
+	return result
+}
+
+// readUntil reads from conn until match reports true, the connection is
+// closed, the deadline expires or maxBannerSize bytes have been read
+func readUntil(conn net.Conn, match func([]byte) bool) ([]byte, bool, error) {
+	reply := make([]byte, 0, 512)
+	buf := make([]byte, 512)
+	for len(reply) < maxBannerSize {
+		n, err := conn.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if match(reply) {
+			return reply, true, nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("connection closed")
+			}
+			return reply, false, err
+		}
+	}
+	return reply, false, nil
+}
+
+// deadlineFor returns the earlier of start+timeout and the context deadline.
+// A zero time is returned when neither is set.
+func deadlineFor(ctx context.Context, start time.Time, timeout time.Duration) time.Time {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = start.Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// startTCPServer starts a local server that writes banner on connect and
+// answers "PING" lines with "+PONG"
+func startTCPServer(t *testing.T, banner string) (string, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				if banner != "" {
+					conn.Write([]byte(banner))
+				}
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if strings.TrimSpace(scanner.Text()) == "PING" {
+						conn.Write([]byte("+PONG\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+func TestTCPChecker(t *testing.T) {
+	addr, port := startTCPServer(t, "SSH-2.0-OpenSSH_9.6\r\n")
+
+	// Find a port with nothing listening on it
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	_, closedPort, _ := net.SplitHostPort(closed.Addr().String())
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		options   map[string]string
+		wantLevel models.Level
+	}{
+		{
+			name:      "connect only",
+			options:   map[string]string{"port": port},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "banner substring",
+			options:   map[string]string{"port": port, "expect": "SSH-2.0"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "banner regex",
+			options:   map[string]string{"port": port, "expect_regex": `^SSH-2\.0-OpenSSH_\d+`},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "send and expect",
+			options:   map[string]string{"port": port, "send": "PING\r\n", "expect": "+PONG"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:      "banner mismatch",
+			options:   map[string]string{"port": port, "expect": "220 smtp"},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "invalid regex",
+			options:   map[string]string{"port": port, "expect_regex": "("},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "missing port",
+			options:   map[string]string{},
+			wantLevel: models.LevelCritical,
+		},
+		{
+			name:      "connection refused",
+			options:   map[string]string{"port": closedPort},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewTCPChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := models.Check{
+				Type:    models.CheckTypeTCP,
+				Enabled: true,
+				Timeout: models.Duration(500 * time.Millisecond),
+				Options: tt.options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "local", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// Default certificate expiry thresholds in days
+const (
+	defaultWarnDays     = 30
+	defaultCriticalDays = 7
+)
+
+// TLSChecker implements TLS certificate expiry and chain validation checks
+type TLSChecker struct{}
+
+// NewTLSChecker creates a new TLS checker
+func NewTLSChecker() *TLSChecker {
+	return &TLSChecker{}
+}
+
+// Type returns the checker type
+func (t *TLSChecker) Type() models.CheckType {
+	return models.CheckTypeTLS
+}
+
+// Check performs a TLS handshake with the host and validates its certificate.
+//
+// Supported options:
+//   - port: TCP port to connect to (default: 443)
+//   - server_name: SNI and expected certificate name (default: host address)
+//   - ca_file: PEM CA bundle used instead of the system pool
+//   - skip_verify: "true" to only check expiry, not the chain or name
+//   - warn_days: warn when the certificate expires within this many days (default: 30)
+//   - critical_days: fail when the certificate expires within this many days (default: 7)
+func (t *TLSChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	result := models.CheckResult{
+		Host:      host.Name,
+		CheckType: models.CheckTypeTLS,
+		Timestamp: time.Now(),
+	}
+
+	if !check.Enabled {
+		result.Level = models.LevelOK
+		result.Message = "Check disabled"
+		return result
+	}
+
+	start := time.Now()
+
+	port := check.Options["port"]
+	if port == "" {
+		port = "443"
+	}
+	serverName := check.Options["server_name"]
+	if serverName == "" {
+		serverName = host.Address
+	}
+
+	warnDays, err := intOption(check.Options, "warn_days", defaultWarnDays)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+	criticalDays, err := intOption(check.Options, "critical_days", defaultCriticalDays)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = err.Error()
+		return result
+	}
+
+	var roots *x509.CertPool
+	if caFile := check.Options["ca_file"]; caFile != "" {
+		roots, err = loadCertPool(caFile)
+		if err != nil {
+			result.Level = models.LevelCritical
+			result.Message = err.Error()
+			return result
+		}
+	}
+
+	// The chain is verified after the handshake so that expiry can be
+	// reported separately from chain and name errors
+	address := net.JoinHostPort(host.Address, port)
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: time.Duration(check.Timeout)},
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("TLS handshake with %s failed: %v", address, err)
+		return result
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("No certificate presented by %s", address)
+		return result
+	}
+	leaf := certs[0]
+
+	expiry := leaf.NotAfter.Format("2006-01-02")
+	if time.Now().After(leaf.NotAfter) {
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Certificate for %s expired on %s", serverName, expiry)
+		return result
+	}
+
+	if check.Options["skip_verify"] != "true" {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       serverName,
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		if err != nil {
+			result.Level = models.LevelCritical
+			result.Message = fmt.Sprintf("Certificate verification failed: %v", err)
+			return result
+		}
+	}
+
+	daysLeft := int(time.Until(leaf.NotAfter).Hours() / 24)
+
+	switch {
+	case daysLeft < criticalDays:
+		result.Level = models.LevelCritical
+		result.Message = fmt.Sprintf("Certificate for %s expires in %d days (%s)", serverName, daysLeft, expiry)
+	case daysLeft < warnDays:
+		result.Level = models.LevelWarning
+		result.Message = fmt.Sprintf("Certificate for %s expires in %d days (%s)", serverName, daysLeft, expiry)
+	default:
+		result.Level = models.LevelOK
+		result.Message = fmt.Sprintf("Certificate for %s valid for %d days (%s)", serverName, daysLeft, expiry)
+	}
+
+	return result
+}
+
+// loadCertPool loads a PEM encoded CA bundle
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read CA file: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("No certificates found in CA file %s", path)
+	}
+	return pool, nil
+}
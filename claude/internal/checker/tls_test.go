@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestTLSChecker(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// The httptest certificate is self-signed, so it doubles as the CA bundle
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	addr, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	tests := []struct {
+		name        string
+		options     map[string]string
+		wantLevel   models.Level
+		wantMessage string
+	}{
+		{
+			name:        "valid with CA bundle",
+			options:     map[string]string{"ca_file": caFile},
+			wantLevel:   models.LevelOK,
+			wantMessage: "valid for",
+		},
+		{
+			name:      "SNI override matching SAN",
+			options:   map[string]string{"ca_file": caFile, "server_name": "example.com"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "SAN mismatch",
+			options:     map[string]string{"ca_file": caFile, "server_name": "other.test"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "verification failed",
+		},
+		{
+			name:        "unknown authority with system pool",
+			options:     map[string]string{},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "verification failed",
+		},
+		{
+			name:      "skip verify",
+			options:   map[string]string{"skip_verify": "true"},
+			wantLevel: models.LevelOK,
+		},
+		{
+			name:        "warn threshold",
+			options:     map[string]string{"ca_file": caFile, "warn_days": "1000000", "critical_days": "0"},
+			wantLevel:   models.LevelWarning,
+			wantMessage: "expires in",
+		},
+		{
+			name:        "critical threshold",
+			options:     map[string]string{"ca_file": caFile, "critical_days": "1000000"},
+			wantLevel:   models.LevelCritical,
+			wantMessage: "expires in",
+		},
+		{
+			name:      "invalid threshold",
+			options:   map[string]string{"warn_days": "soon"},
+			wantLevel: models.LevelCritical,
+		},
+	}
+
+	checker := NewTLSChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]string{"port": port}
+			for k, v := range tt.options {
+				options[k] = v
+			}
+			check := models.Check{
+				Type:    models.CheckTypeTLS,
+				Enabled: true,
+				Timeout: models.Duration(time.Second),
+				Options: options,
+			}
+			result := checker.Check(context.Background(), models.Host{Name: "tls", Address: addr}, check)
+			if result.Level != tt.wantLevel {
+				t.Errorf("Check() level = %v, want %v (message: %s)", result.Level, tt.wantLevel, result.Message)
+			}
+			if tt.wantMessage != "" && !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Check() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/maintenance"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/schedule"
 	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
 	"github.com/pelletier/go-toml/v2"
 	"gopkg.in/yaml.v3"
@@ -42,6 +44,9 @@ func LoadConfig(path string) (*models.Config, error) {
 	if cfg.WebServerPort == 0 {
 		cfg.WebServerPort = 8080
 	}
+	if cfg.MaxConcurrentChecks == 0 {
+		cfg.MaxConcurrentChecks = 10
+	}
 	// EnableConsoleLog defaults to false (zero value)
 
 	// Validate configuration
@@ -57,6 +62,15 @@ func validateConfig(cfg *models.Config) error {
 	if len(cfg.Hosts) == 0 {
 		return fmt.Errorf("no hosts configured")
 	}
+	if cfg.MaxConcurrentChecks < 0 {
+		return fmt.Errorf("max_concurrent_checks must not be negative")
+	}
+	if cfg.TargetConcurrency < 0 {
+		return fmt.Errorf("target_concurrency must not be negative")
+	}
+	if cfg.TargetInterval < 0 {
+		return fmt.Errorf("target_interval must not be negative")
+	}
 
 	for i, host := range cfg.Hosts {
 		if host.Name == "" {
@@ -73,12 +87,245 @@ func validateConfig(cfg *models.Config) error {
 			if check.Type == "" {
 				return fmt.Errorf("host %s check at index %d has no type", host.Name, j)
 			}
+			if err := ValidateCheck(check); err != nil {
+				return fmt.Errorf("host %s %s check: %w", host.Name, check.Type, err)
+			}
 			if check.Timeout == 0 {
 				cfg.Hosts[i].Checks[j].Timeout = models.Duration(5 * time.Second) // 5 seconds default
 			}
 		}
 	}
 
+	if err := ValidateHeartbeats(cfg.Hosts); err != nil {
+		return err
+	}
+	if err := ValidateDependencies(cfg.Hosts); err != nil {
+		return err
+	}
+	if err := ValidateNotifications(cfg.Notifications, cfg.Hosts); err != nil {
+		return err
+	}
+
+	for i, window := range cfg.Maintenance {
+		if err := maintenance.Validate(window); err != nil {
+			if window.Name == "" {
+				return fmt.Errorf("maintenance window at index %d: %w", i, err)
+			}
+			return fmt.Errorf("maintenance window %s: %w", window.Name, err)
+		}
+	}
+	for i, silence := range cfg.Silences {
+		if silence.Host == "" || silence.Expires.IsZero() {
+			return fmt.Errorf("silence at index %d needs a host and an expiry", i)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCheck checks the schedule, failure thresholds and flap detection
+// settings of a check
+func ValidateCheck(check models.Check) error {
+	if check.Interval != 0 || check.Schedule != "" {
+		if _, err := schedule.Parse(check, 0); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case check.FailAfter < 0:
+		return fmt.Errorf("fail_after must not be negative")
+	case check.RecoverAfter < 0:
+		return fmt.Errorf("recover_after must not be negative")
+	case check.Retries < 0:
+		return fmt.Errorf("retries must not be negative")
+	case check.RetryDelay < 0:
+		return fmt.Errorf("retry_delay must not be negative")
+	case check.FlapThreshold < 0:
+		return fmt.Errorf("flap_threshold must not be negative")
+	case check.FlapWindow < 0:
+		return fmt.Errorf("flap_window must not be negative")
+	case check.Warn < 0:
+		return fmt.Errorf("warn must not be negative")
+	case check.Critical < 0:
+		return fmt.Errorf("critical must not be negative")
+	case check.Warn > 0 && check.Critical > 0 && check.Warn >= check.Critical:
+		return fmt.Errorf("warn must be less than critical")
+	case check.FailingInterval != 0 && time.Duration(check.FailingInterval) < schedule.MinInterval:
+		return fmt.Errorf("failing_interval must be at least %v", schedule.MinInterval)
+	case check.BackoffAfter < 0:
+		return fmt.Errorf("backoff_after must not be negative")
+	case check.BackoffMax != 0 && time.Duration(check.BackoffMax) < schedule.MinInterval:
+		return fmt.Errorf("backoff_max must be at least %v", schedule.MinInterval)
+	case check.BackoffMax > 0 && check.FailingInterval > 0 && check.BackoffMax <= check.FailingInterval:
+		return fmt.Errorf("backoff_max must be longer than failing_interval")
+	}
+	return nil
+}
+
+// ValidateDependencies checks that every host depends on existing hosts or
+// checks and that the dependencies contain no cycles. A check reference must
+// name a single check, so hosts with several checks of the type cannot be
+// depended on through that type.
+func ValidateDependencies(hosts []models.Host) error {
+	byName := make(map[string]models.Host, len(hosts))
+	for _, host := range hosts {
+		byName[host.Name] = host
+	}
+
+	// parents maps each host to the hosts it depends on, directly or through one of their checks
+	parents := make(map[string][]string, len(hosts))
+	for _, host := range hosts {
+		for _, ref := range host.DependsOn {
+			parent, err := resolveDependency(byName, ref)
+			if err != nil {
+				return fmt.Errorf("host %s depends_on: %w", host.Name, err)
+			}
+			parents[host.Name] = append(parents[host.Name], parent)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(hosts))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path[start:], " -> "), name)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+		for _, parent := range parents[name] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+
+	for _, host := range hosts {
+		if err := visit(host.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateNotifications checks that notification channels have unique names
+// and a type and that hosts only notify channels that exist. The options of
+// each channel are checked by its notifier.
+func ValidateNotifications(channels []models.Channel, hosts []models.Host) error {
+	names := make(map[string]bool, len(channels))
+	for i, channel := range channels {
+		if channel.Name == "" {
+			return fmt.Errorf("notification channel at index %d has no name", i)
+		}
+		if names[channel.Name] {
+			return fmt.Errorf("notification channel %s is defined more than once", channel.Name)
+		}
+		if channel.Type == "" {
+			return fmt.Errorf("notification channel %s has no type", channel.Name)
+		}
+		names[channel.Name] = true
+	}
+
+	for _, host := range hosts {
+		for _, name := range host.Notify {
+			if !names[name] {
+				return fmt.Errorf("host %s notify: unknown notification channel %q", host.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveDependency returns the name of the host a dependency refers to,
+// either directly or through the only check of a type on the host
+func resolveDependency(hosts map[string]models.Host, ref string) (string, error) {
+	if _, ok := hosts[ref]; ok {
+		return ref, nil
+	}
+
+	hostName, checkType, ok := models.SplitCheckRef(ref)
+	if !ok {
+		return "", fmt.Errorf("unknown host %q", ref)
+	}
+	host, ok := hosts[hostName]
+	if !ok {
+		return "", fmt.Errorf("unknown host %q", hostName)
+	}
+	count := 0
+	for _, check := range host.Checks {
+		if check.Type == checkType {
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return "", fmt.Errorf("host %s has no %s check", hostName, checkType)
+	case 1:
+		return hostName, nil
+	}
+	return "", fmt.Errorf("%s is ambiguous: host %s has %d %s checks", ref, hostName, count, checkType)
+}
+
+// ValidateHeartbeats checks the options of every heartbeat check. Tokens
+// identify the check in ping URLs, so they must be unique across all hosts.
+func ValidateHeartbeats(hosts []models.Host) error {
+	tokens := make(map[string]bool)
+	for _, host := range hosts {
+		for _, check := range host.Checks {
+			if check.Type != models.CheckTypeHeartbeat {
+				continue
+			}
+			if err := validateHeartbeat(check, tokens); err != nil {
+				return fmt.Errorf("host %s heartbeat check: %w", host.Name, err)
+			}
+			tokens[check.Options["token"]] = true
+		}
+	}
+	return nil
+}
+
+// validateHeartbeat checks the options of a heartbeat check against the
+// tokens already in use
+func validateHeartbeat(check models.Check, tokens map[string]bool) error {
+	token := check.Options["token"]
+	if token == "" {
+		return fmt.Errorf("no token configured")
+	}
+	if strings.Contains(token, "/") {
+		return fmt.Errorf("token %q must not contain '/'", token)
+	}
+	if tokens[token] {
+		return fmt.Errorf("token %q is used by more than one check", token)
+	}
+
+	period, err := time.ParseDuration(check.Options["period"])
+	if err != nil || period <= 0 {
+		return fmt.Errorf("invalid period %q", check.Options["period"])
+	}
+	for _, name := range []string{"grace", "start_timeout"} {
+		if raw := check.Options[name]; raw != "" {
+			if d, err := time.ParseDuration(raw); err != nil || d < 0 {
+				return fmt.Errorf("invalid %s %q", name, raw)
+			}
+		}
+	}
 	return nil
 }
 
@@ -120,6 +120,27 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "target limits",
+			config: &models.Config{
+				TargetConcurrency: 2,
+				TargetInterval:    models.Duration(200 * time.Millisecond),
+				Hosts: []models.Host{
+					{Name: "test", Address: "127.0.0.1", Checks: []models.Check{{Type: models.CheckTypePing, Enabled: true}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative target_concurrency",
+			config: &models.Config{
+				TargetConcurrency: -1,
+				Hosts: []models.Host{
+					{Name: "test", Address: "127.0.0.1", Checks: []models.Check{{Type: models.CheckTypePing, Enabled: true}}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "no hosts",
 			config: &models.Config{
@@ -141,6 +162,371 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid heartbeat",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHeartbeat, Options: map[string]string{"token": "nightly", "period": "24h", "grace": "1h"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "heartbeat without period",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHeartbeat, Options: map[string]string{"token": "nightly"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "heartbeat with invalid start timeout",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHeartbeat, Options: map[string]string{"token": "nightly", "period": "24h", "start_timeout": "soon"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate heartbeat token",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHeartbeat, Options: map[string]string{"token": "nightly", "period": "24h"}},
+						},
+					},
+					{
+						Name:    "reports",
+						Address: "reports.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHeartbeat, Options: map[string]string{"token": "nightly", "period": "1h"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid interval and schedule",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, Interval: models.Duration(10 * time.Second)},
+							{Type: models.CheckTypeTCP, Schedule: "0 * * * *"},
+							{Type: models.CheckTypePing, Schedule: "@every 30s"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "interval too short",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, Interval: models.Duration(100 * time.Millisecond)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid cron schedule",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypePing, Schedule: "0 25 * * *"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid thresholds",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, FailAfter: 3, RecoverAfter: 2, Retries: 1, RetryDelay: models.Duration(time.Second), FlapThreshold: 4, FlapWindow: models.Duration(time.Hour)},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative fail_after",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, FailAfter: -1},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "latency thresholds",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, Warn: models.Duration(time.Second), Critical: models.Duration(3 * time.Second)},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "warn above critical",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, Warn: models.Duration(3 * time.Second), Critical: models.Duration(time.Second)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative critical",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, Critical: models.Duration(-time.Second)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "adaptive intervals",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, FailingInterval: models.Duration(10 * time.Second), BackoffAfter: models.Duration(time.Hour), BackoffMax: models.Duration(30 * time.Minute)},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failing_interval too short",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, FailingInterval: models.Duration(time.Millisecond)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "backoff_max below failing_interval",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "payments",
+						Address: "payments.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypeHTTP, FailingInterval: models.Duration(time.Minute), BackoffMax: models.Duration(30 * time.Second)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid maintenance windows",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "db", Address: "db.internal", Tags: []string{"database"}, Checks: []models.Check{{Type: models.CheckTypeTCP}}},
+				},
+				Maintenance: []models.MaintenanceWindow{
+					{Name: "patching", Tags: []string{"database"}, Timezone: "Europe/London", Days: []string{"sun"}, At: "02:00", Duration: models.Duration(2 * time.Hour)},
+					{Name: "migration", Checks: []string{"db/tcp"}, Start: "2025-03-01 22:00", End: "2025-03-02 02:00"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "maintenance window without scope",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "db", Address: "db.internal", Checks: []models.Check{{Type: models.CheckTypeTCP}}},
+				},
+				Maintenance: []models.MaintenanceWindow{
+					{Name: "patching", Days: []string{"sun"}, At: "02:00", Duration: models.Duration(time.Hour)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid dependencies",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "router", Address: "192.168.1.1", Checks: []models.Check{{Type: models.CheckTypePing}}},
+					{Name: "nas", Address: "192.168.1.10", DependsOn: []string{"router"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+					{Name: "backups", Address: "192.168.1.10", DependsOn: []string{"router/ping", "nas"}, Checks: []models.Check{{Type: models.CheckTypeTCP}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "dependency on unknown check",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "router", Address: "192.168.1.1", Checks: []models.Check{{Type: models.CheckTypePing}}},
+					{Name: "nas", Address: "192.168.1.10", DependsOn: []string{"router/http"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dependency on one of several checks of a type",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "router", Address: "192.168.1.1", Checks: []models.Check{{Type: models.CheckTypeHTTP}, {Type: models.CheckTypeHTTP}}},
+					{Name: "nas", Address: "192.168.1.10", DependsOn: []string{"router/http"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dependency cycle",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "a", Address: "10.0.0.1", DependsOn: []string{"c/ping"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+					{Name: "b", Address: "10.0.0.2", DependsOn: []string{"a"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+					{Name: "c", Address: "10.0.0.3", DependsOn: []string{"b"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "host depends on itself",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{Name: "a", Address: "10.0.0.1", DependsOn: []string{"a/ping"}, Checks: []models.Check{{Type: models.CheckTypePing}, {Type: models.CheckTypeHTTP}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "host notifies channel",
+			config: &models.Config{
+				Notifications: []models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook, Options: map[string]string{"url": "https://example.com/hook"}}},
+				Hosts: []models.Host{
+					{Name: "web", Address: "10.0.0.1", Notify: []string{"ops"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "host notifies unknown channel",
+			config: &models.Config{
+				Notifications: []models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook}},
+				Hosts: []models.Host{
+					{Name: "web", Address: "10.0.0.1", Notify: []string{"dev"}, Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate notification channel",
+			config: &models.Config{
+				Notifications: []models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook}, {Name: "ops", Type: models.ChannelTypeWebhook}},
+				Hosts: []models.Host{
+					{Name: "web", Address: "10.0.0.1", Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "notification channel without type",
+			config: &models.Config{
+				Notifications: []models.Channel{{Name: "ops"}},
+				Hosts: []models.Host{
+					{Name: "web", Address: "10.0.0.1", Checks: []models.Check{{Type: models.CheckTypePing}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "interval and schedule both set",
+			config: &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "backup",
+						Address: "backup.internal",
+						Checks: []models.Check{
+							{Type: models.CheckTypePing, Interval: models.Duration(time.Hour), Schedule: "@hourly"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
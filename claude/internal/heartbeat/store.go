@@ -0,0 +1,93 @@
+// Package heartbeat records pings sent by cron jobs and batch processes for
+// passive heartbeat checks.
+package heartbeat
+
+import (
+	"sync"
+	"time"
+)
+
+// Signal is the kind of ping received from a job
+type Signal string
+
+const (
+	// SignalSuccess reports that the job completed successfully
+	SignalSuccess Signal = "success"
+	// SignalStart reports that the job has started
+	SignalStart Signal = "start"
+	// SignalFail reports that the job failed
+	SignalFail Signal = "fail"
+)
+
+// State is the ping history for a single heartbeat token
+type State struct {
+	// LastPing is the time of the last success or fail ping
+	LastPing time.Time
+	// LastStart is the time the current run started, zero when no run is in progress
+	LastStart time.Time
+	// Failed is true when the last completed run reported a failure
+	Failed bool
+	// RunTime is the duration of the last run that sent a start ping
+	RunTime time.Duration
+	// Message is the body sent with the last ping, if any
+	Message string
+}
+
+// Store holds heartbeat state keyed by token
+type Store struct {
+	created time.Time
+	states  map[string]*State
+	mu      sync.RWMutex
+}
+
+// NewStore creates an empty heartbeat store
+func NewStore() *Store {
+	return &Store{
+		created: time.Now(),
+		states:  make(map[string]*State),
+	}
+}
+
+// Created returns when the store was created, used as the reference time for
+// heartbeats that have never been pinged
+func (s *Store) Created() time.Time {
+	return s.created
+}
+
+// Record records a ping for token received at the given time
+func (s *Store) Record(token string, signal Signal, message string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[token]
+	if !ok {
+		state = &State{}
+		s.states[token] = state
+	}
+
+	if signal == SignalStart {
+		state.LastStart = at
+		return
+	}
+
+	state.RunTime = 0
+	if !state.LastStart.IsZero() {
+		state.RunTime = at.Sub(state.LastStart)
+	}
+	state.LastStart = time.Time{}
+	state.LastPing = at
+	state.Failed = signal == SignalFail
+	state.Message = message
+}
+
+// Get returns the state for token and whether any ping has been received for it
+func (s *Store) Get(token string) (State, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, ok := s.states[token]
+	if !ok {
+		return State{}, false
+	}
+	return *state, true
+}
@@ -0,0 +1,219 @@
+package maintenance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// dateTimeLayout is the layout for the start and end of one-off windows,
+// interpreted in the window's time zone. RFC 3339 times are accepted as well.
+const dateTimeLayout = "2006-01-02 15:04"
+
+// timeOfDayLayout is the layout for the start time of weekly windows
+const timeOfDayLayout = "15:04"
+
+// maxWeeklyDuration is the longest a weekly window may last
+const maxWeeklyDuration = 7 * 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Active returns why a check is in maintenance at the given time, because of
+// either a silence or a maintenance window. It returns false if neither applies.
+func Active(cfg *models.Config, host models.Host, check models.Check, now time.Time) (string, bool) {
+	for _, silence := range cfg.Silences {
+		if !silence.Active(now) || silence.Host != host.Name {
+			continue
+		}
+		if silence.Check == "" || silence.Check == check.Type {
+			return fmt.Sprintf("Silenced until %s: %s", silence.Expires.Local().Format("Jan 2 15:04"), silence.Reason), true
+		}
+	}
+
+	for _, window := range cfg.Maintenance {
+		if matches(window, host, check) && windowActive(window, now) {
+			if window.Name == "" {
+				return "Maintenance window", true
+			}
+			return fmt.Sprintf("Maintenance window %s", window.Name), true
+		}
+	}
+
+	return "", false
+}
+
+// Validate checks the scope and schedule of a maintenance window
+func Validate(window models.MaintenanceWindow) error {
+	if len(window.Hosts) == 0 && len(window.Tags) == 0 && len(window.Checks) == 0 {
+		return fmt.Errorf("no hosts, tags or checks configured")
+	}
+	for _, check := range window.Checks {
+		if _, _, ok := models.SplitCheckRef(check); !ok {
+			return fmt.Errorf("check %q must be written as host/type", check)
+		}
+	}
+
+	loc, err := location(window)
+	if err != nil {
+		return err
+	}
+
+	oneOff := window.Start != "" || window.End != ""
+	weekly := len(window.Days) > 0 || window.At != "" || window.Duration != 0
+
+	switch {
+	case oneOff && weekly:
+		return fmt.Errorf("start and end cannot be combined with days, at and duration")
+	case oneOff:
+		start, err := parseDateTime(window.Start, loc)
+		if err != nil {
+			return fmt.Errorf("invalid start %q", window.Start)
+		}
+		end, err := parseDateTime(window.End, loc)
+		if err != nil {
+			return fmt.Errorf("invalid end %q", window.End)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("end must be after start")
+		}
+	case weekly:
+		if len(window.Days) == 0 {
+			return fmt.Errorf("no days configured")
+		}
+		for _, day := range window.Days {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("invalid day %q", day)
+			}
+		}
+		if _, err := time.Parse(timeOfDayLayout, window.At); err != nil {
+			return fmt.Errorf("invalid at %q, expected HH:MM", window.At)
+		}
+		if window.Duration <= 0 || time.Duration(window.Duration) > maxWeeklyDuration {
+			return fmt.Errorf("duration must be between 0 and %v", maxWeeklyDuration)
+		}
+	default:
+		return fmt.Errorf("no start and end or weekly days, at and duration configured")
+	}
+
+	return nil
+}
+
+// NewSilence creates a silence with a random ID
+func NewSilence(host string, check models.CheckType, reason string, now, expires time.Time) (models.Silence, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return models.Silence{}, fmt.Errorf("failed to generate silence ID: %w", err)
+	}
+
+	return models.Silence{
+		ID:      hex.EncodeToString(id),
+		Host:    host,
+		Check:   check,
+		Reason:  reason,
+		Created: now,
+		Expires: expires,
+	}, nil
+}
+
+// matches reports whether a window applies to a check
+func matches(window models.MaintenanceWindow, host models.Host, check models.Check) bool {
+	for _, name := range window.Hosts {
+		if name == "*" || name == host.Name {
+			return true
+		}
+	}
+	for _, tag := range window.Tags {
+		for _, hostTag := range host.Tags {
+			if tag == hostTag {
+				return true
+			}
+		}
+	}
+	for _, c := range window.Checks {
+		if hostName, checkType, ok := models.SplitCheckRef(c); ok && hostName == host.Name && checkType == check.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// windowActive reports whether a valid window is active at the given time
+func windowActive(window models.MaintenanceWindow, now time.Time) bool {
+	loc, err := location(window)
+	if err != nil {
+		return false
+	}
+
+	if window.Start != "" {
+		start, err := parseDateTime(window.Start, loc)
+		if err != nil {
+			return false
+		}
+		end, err := parseDateTime(window.End, loc)
+		if err != nil {
+			return false
+		}
+		return !now.Before(start) && now.Before(end)
+	}
+
+	at, err := time.Parse(timeOfDayLayout, window.At)
+	if err != nil {
+		return false
+	}
+
+	// A window that started on one of the previous days may still be running
+	local := now.In(loc)
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, -offset)
+		if !onDay(window.Days, day.Weekday()) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+		if !local.Before(start) && local.Before(start.Add(time.Duration(window.Duration))) {
+			return true
+		}
+	}
+	return false
+}
+
+// onDay reports whether the weekday is one of the configured days
+func onDay(days []string, weekday time.Weekday) bool {
+	for _, day := range days {
+		if d, ok := weekdays[strings.ToLower(day)]; ok && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the time zone of a window, defaulting to local time
+func location(window models.MaintenanceWindow) (*time.Location, error) {
+	if window.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", window.Timezone)
+	}
+	return loc, nil
+}
+
+// parseDateTime parses the start or end of a one-off window
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateTimeLayout, value, loc); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,232 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestWindowActive(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("Time zone data not available: %v", err)
+	}
+
+	// Sunday 2 March 2025 is in GMT, Sunday 6 April 2025 is in BST
+	tests := []struct {
+		name   string
+		window models.MaintenanceWindow
+		now    time.Time
+		want   bool
+	}{
+		{
+			name:   "one-off inside",
+			window: models.MaintenanceWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00", Timezone: "Europe/London"},
+			now:    time.Date(2025, 3, 2, 1, 0, 0, 0, london),
+			want:   true,
+		},
+		{
+			name:   "one-off at end",
+			window: models.MaintenanceWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00", Timezone: "Europe/London"},
+			now:    time.Date(2025, 3, 2, 2, 0, 0, 0, london),
+			want:   false,
+		},
+		{
+			name:   "one-off RFC 3339",
+			window: models.MaintenanceWindow{Start: "2025-03-01T22:00:00Z", End: "2025-03-02T02:00:00Z"},
+			now:    time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "weekly inside",
+			window: models.MaintenanceWindow{Days: []string{"sun"}, At: "02:00", Duration: models.Duration(2 * time.Hour), Timezone: "Europe/London"},
+			now:    time.Date(2025, 3, 2, 3, 30, 0, 0, london),
+			want:   true,
+		},
+		{
+			name:   "weekly follows daylight saving time",
+			window: models.MaintenanceWindow{Days: []string{"Sunday"}, At: "02:00", Duration: models.Duration(time.Hour), Timezone: "Europe/London"},
+			now:    time.Date(2025, 4, 6, 1, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "weekly other day",
+			window: models.MaintenanceWindow{Days: []string{"sun"}, At: "02:00", Duration: models.Duration(2 * time.Hour), Timezone: "Europe/London"},
+			now:    time.Date(2025, 3, 3, 3, 0, 0, 0, london),
+			want:   false,
+		},
+		{
+			name:   "weekly crosses midnight",
+			window: models.MaintenanceWindow{Days: []string{"sat"}, At: "23:00", Duration: models.Duration(4 * time.Hour), Timezone: "Europe/London"},
+			now:    time.Date(2025, 3, 2, 1, 0, 0, 0, london),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(models.MaintenanceWindow{
+				Hosts: []string{"*"}, Start: tt.window.Start, End: tt.window.End, Timezone: tt.window.Timezone,
+				Days: tt.window.Days, At: tt.window.At, Duration: tt.window.Duration,
+			}); err != nil {
+				t.Fatalf("Expected a valid window, got %v", err)
+			}
+			if got := windowActive(tt.window, tt.now); got != tt.want {
+				t.Errorf("Expected active %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  models.MaintenanceWindow
+		wantErr string
+	}{
+		{
+			name:    "no scope",
+			window:  models.MaintenanceWindow{Start: "2025-03-01 22:00", End: "2025-03-02 02:00"},
+			wantErr: "no hosts, tags or checks",
+		},
+		{
+			name:    "bad check reference",
+			window:  models.MaintenanceWindow{Checks: []string{"db"}, Start: "2025-03-01 22:00", End: "2025-03-02 02:00"},
+			wantErr: "host/type",
+		},
+		{
+			name:    "end before start",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}, Start: "2025-03-02 02:00", End: "2025-03-01 22:00"},
+			wantErr: "end must be after start",
+		},
+		{
+			name:    "both forms",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}, Start: "2025-03-01 22:00", End: "2025-03-02 02:00", Days: []string{"sun"}},
+			wantErr: "cannot be combined",
+		},
+		{
+			name:    "unknown timezone",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}, Timezone: "Mars/Olympus", Days: []string{"sun"}, At: "02:00", Duration: models.Duration(time.Hour)},
+			wantErr: "invalid timezone",
+		},
+		{
+			name:    "unknown day",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}, Days: []string{"someday"}, At: "02:00", Duration: models.Duration(time.Hour)},
+			wantErr: "invalid day",
+		},
+		{
+			name:    "no duration",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}, Days: []string{"sun"}, At: "02:00"},
+			wantErr: "duration",
+		},
+		{
+			name:    "no schedule",
+			window:  models.MaintenanceWindow{Hosts: []string{"db"}},
+			wantErr: "no start and end",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.window)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestActive(t *testing.T) {
+	now := time.Date(2025, 3, 2, 1, 0, 0, 0, time.UTC)
+	db := models.Host{Name: "db", Tags: []string{"database"}}
+	web := models.Host{Name: "web"}
+	tcp := models.Check{Type: models.CheckTypeTCP}
+	ping := models.Check{Type: models.CheckTypePing}
+	window := func(w models.MaintenanceWindow) models.MaintenanceWindow {
+		w.Name = "patching"
+		w.Start = "2025-03-01T22:00:00Z"
+		w.End = "2025-03-02T02:00:00Z"
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		cfg      models.Config
+		host     models.Host
+		check    models.Check
+		wantOK   bool
+		wantText string
+	}{
+		{
+			name:     "window by tag",
+			cfg:      models.Config{Maintenance: []models.MaintenanceWindow{window(models.MaintenanceWindow{Tags: []string{"database"}})}},
+			host:     db,
+			check:    tcp,
+			wantOK:   true,
+			wantText: "Maintenance window patching",
+		},
+		{
+			name:   "window by tag on other host",
+			cfg:    models.Config{Maintenance: []models.MaintenanceWindow{window(models.MaintenanceWindow{Tags: []string{"database"}})}},
+			host:   web,
+			check:  tcp,
+			wantOK: false,
+		},
+		{
+			name:   "window for all hosts",
+			cfg:    models.Config{Maintenance: []models.MaintenanceWindow{window(models.MaintenanceWindow{Hosts: []string{"*"}})}},
+			host:   web,
+			check:  ping,
+			wantOK: true,
+		},
+		{
+			name:   "window by check",
+			cfg:    models.Config{Maintenance: []models.MaintenanceWindow{window(models.MaintenanceWindow{Checks: []string{"db/tcp"}})}},
+			host:   db,
+			check:  ping,
+			wantOK: false,
+		},
+		{
+			name: "silence for whole host",
+			cfg: models.Config{Silences: []models.Silence{
+				{Host: "db", Reason: "Replacing disk", Expires: now.Add(time.Hour)},
+			}},
+			host:     db,
+			check:    ping,
+			wantOK:   true,
+			wantText: "Replacing disk",
+		},
+		{
+			name: "silence for other check",
+			cfg: models.Config{Silences: []models.Silence{
+				{Host: "db", Check: models.CheckTypeTCP, Reason: "Firewall change", Expires: now.Add(time.Hour)},
+			}},
+			host:   db,
+			check:  ping,
+			wantOK: false,
+		},
+		{
+			name: "expired silence",
+			cfg: models.Config{Silences: []models.Silence{
+				{Host: "db", Reason: "Replacing disk", Expires: now.Add(-time.Minute)},
+			}},
+			host:   db,
+			check:  tcp,
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := Active(&tt.cfg, tt.host, tt.check, now)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected active %v, got %v (%q)", tt.wantOK, ok, reason)
+			}
+			if !strings.Contains(reason, tt.wantText) {
+				t.Errorf("Expected reason containing %q, got %q", tt.wantText, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// chatMessage is the content of a chat notification. Each chat notifier
+// renders it in the rich format of its service.
+type chatMessage struct {
+	Title   string // One line summary, also the plain text fallback
+	Facts   []fact
+	Message string // The message of the check result
+	Link    string // The dashboard
+	Color   int    // RGB color for the state
+	Time    time.Time
+}
+
+// fact is a labelled value shown in a chat notification
+type fact struct {
+	Name  string
+	Value string
+}
+
+// Colors for chat notifications
+const (
+	colorDown     = 0xE01E5A
+	colorFlapping = 0xECB22E
+	colorUp       = 0x2EB67D
+	colorOther    = 0x868E96
+)
+
+// newChatMessage builds the chat notification for an event
+func newChatMessage(event Event) chatMessage {
+	p := NewPayload(event)
+	check := fmt.Sprintf("%s %s check", p.Host, p.Check)
+
+	msg := chatMessage{Message: p.Message, Link: p.DashboardURL, Time: p.Timestamp}
+	switch {
+	case p.Recovered() && p.Outage > 0:
+		msg.Title = fmt.Sprintf("✅ %s recovered after %s", check, formatOutage(p.Outage))
+		msg.Color = colorUp
+	case p.State == models.StateUp:
+		msg.Title = fmt.Sprintf("✅ %s is UP", check)
+		msg.Color = colorUp
+	case p.State == models.StateDown:
+		msg.Title = fmt.Sprintf("🔴 %s is DOWN", check)
+		msg.Color = colorDown
+	case p.State == models.StateFlapping:
+		msg.Title = fmt.Sprintf("🟠 %s is FLAPPING", check)
+		msg.Color = colorFlapping
+	default:
+		msg.Title = fmt.Sprintf("%s is %s", check, p.State)
+		msg.Color = colorOther
+	}
+
+	host := p.Host
+	if p.Address != "" && p.Address != p.Host {
+		host = fmt.Sprintf("%s (%s)", p.Host, p.Address)
+	}
+	msg.Facts = []fact{
+		{"Host", host},
+		{"Check", string(p.Check)},
+		{"State", fmt.Sprintf("%s → %s", p.Previous, p.State)},
+		{"Level", string(p.Level)},
+		{"Latency", p.Latency.Round(time.Millisecond).String()},
+	}
+	if p.Outage > 0 {
+		msg.Facts = append(msg.Facts, fact{"Outage", formatOutage(p.Outage)})
+	}
+	return msg
+}
+
+// formatOutage rounds an outage to whole seconds, or minutes once it is
+// longer than an hour
+func formatOutage(d time.Duration) string {
+	if d >= time.Hour {
+		return d.Round(time.Minute).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// truncate shortens s to at most n runes, as chat services reject long fields
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// validateChat checks the options shared by the chat notifiers
+func validateChat(channel models.Channel) error {
+	if err := validateURL(channel.Options["url"]); err != nil {
+		return err
+	}
+	_, err := parseRetryPolicy(channel.Options)
+	return err
+}
+
+// postJSON sends a chat message encoded as JSON to the incoming webhook URL of a channel
+func postJSON(ctx context.Context, client *http.Client, channel models.Channel, message any) error {
+	if err := validateURL(channel.Options["url"]); err != nil {
+		return err
+	}
+	policy, err := parseRetryPolicy(channel.Options)
+	if err != nil {
+		return err
+	}
+	return sendJSON(ctx, client, policy, channel.Options["url"], nil, message)
+}
@@ -0,0 +1,138 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func recoveryEvent() Event {
+	since := time.Unix(1700000000, 0).UTC()
+	return Event{
+		Host:         models.Host{Name: "web-1", Address: "10.0.0.1"},
+		Check:        models.Check{Type: models.CheckTypeHTTP},
+		Result:       models.CheckResult{Level: models.LevelOK, Message: "status <200>", Duration: 42 * time.Millisecond, Timestamp: since},
+		Status:       models.Status{State: models.StateUp, Previous: models.StateDown, Since: since, PrevSince: since.Add(-5*time.Minute - 30*time.Second)},
+		DashboardURL: "https://status.example.com",
+	}
+}
+
+func TestNewChatMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     Event
+		wantTitle string
+		wantColor int
+		wantFacts map[string]string
+	}{
+		{
+			name:      "down",
+			event:     testEvent(),
+			wantTitle: "🔴 web-1 http check is DOWN",
+			wantColor: colorDown,
+			wantFacts: map[string]string{"Host": "web-1 (10.0.0.1)", "State": "UP → DOWN", "Level": "CRITICAL", "Latency": "1.5s"},
+		},
+		{
+			name:      "recovery",
+			event:     recoveryEvent(),
+			wantTitle: "✅ web-1 http check recovered after 5m30s",
+			wantColor: colorUp,
+			wantFacts: map[string]string{"State": "DOWN → UP", "Latency": "42ms", "Outage": "5m30s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newChatMessage(tt.event)
+			if msg.Title != tt.wantTitle {
+				t.Errorf("Expected title %q, got %q", tt.wantTitle, msg.Title)
+			}
+			if msg.Color != tt.wantColor {
+				t.Errorf("Expected color %06x, got %06x", tt.wantColor, msg.Color)
+			}
+			facts := make(map[string]string)
+			for _, f := range msg.Facts {
+				facts[f.Name] = f.Value
+			}
+			for name, want := range tt.wantFacts {
+				if facts[name] != want {
+					t.Errorf("Expected %s %q, got %q", name, want, facts[name])
+				}
+			}
+		})
+	}
+}
+
+func TestChatNotifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier Notifier
+		// wantParts are strings the JSON body must contain
+		wantParts []string
+	}{
+		{
+			name:     "slack",
+			notifier: NewSlackNotifier(),
+			wantParts: []string{
+				`"type":"header"`,
+				`recovered after 5m30s`,
+				`"text":"*Message*\nstatus &lt;200&gt;"`,
+				`<https://status.example.com|Open dashboard>`,
+			},
+		},
+		{
+			name:     "discord",
+			notifier: NewDiscordNotifier(),
+			wantParts: []string{
+				`"embeds":[`,
+				`"url":"https://status.example.com"`,
+				`"color":3061373`,
+				`{"name":"Outage","value":"5m30s","inline":true}`,
+				`"description":"status <200>"`,
+			},
+		},
+		{
+			name:     "teams",
+			notifier: NewTeamsNotifier(),
+			wantParts: []string{
+				`"contentType":"application/vnd.microsoft.card.adaptive"`,
+				`"type":"AdaptiveCard"`,
+				`{"title":"Outage","value":"5m30s"}`,
+				`"color":"Good"`,
+				`{"type":"Action.OpenUrl","title":"Open dashboard","url":"https://status.example.com"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newWebhookTestServer(t, http.StatusOK)
+
+			channel := models.Channel{Name: tt.name, Type: tt.notifier.Type(), Options: map[string]string{"url": server.URL}}
+			if err := tt.notifier.(Validator).Validate(channel); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if err := tt.notifier.Notify(context.Background(), channel, recoveryEvent()); err != nil {
+				t.Fatalf("Notify failed: %v", err)
+			}
+
+			got := requests()
+			if len(got) != 1 {
+				t.Fatalf("Expected 1 request, got %d", len(got))
+			}
+			if !json.Valid(got[0].body) {
+				t.Fatalf("Expected a JSON body, got %s", got[0].body)
+			}
+			for _, part := range tt.wantParts {
+				if !strings.Contains(string(got[0].body), part) {
+					t.Errorf("Expected body to contain %s, got %s", part, got[0].body)
+				}
+			}
+		})
+	}
+}
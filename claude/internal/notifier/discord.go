@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DiscordNotifier posts state changes to a Discord webhook as embeds.
+//
+// Options:
+//   - url: the webhook URL (required)
+//   - username: overrides the name the webhook posts as
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type DiscordNotifier struct {
+	httpClient *http.Client
+}
+
+// NewDiscordNotifier creates a new Discord notifier
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (d *DiscordNotifier) Type() models.ChannelType {
+	return models.ChannelTypeDiscord
+}
+
+// Validate checks the webhook URL and retry options of the channel
+func (d *DiscordNotifier) Validate(channel models.Channel) error {
+	return validateChat(channel)
+}
+
+// Notify posts the event to Discord
+func (d *DiscordNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	message := discordMessage(newChatMessage(event))
+	message.Username = channel.Options["username"]
+	return postJSON(ctx, d.httpClient, channel, message)
+}
+
+type discordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields"`
+	Timestamp   string         `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordMessage renders a chat message as a Discord embed, titled with a
+// link to the dashboard and colored by state
+func discordMessage(msg chatMessage) discordPayload {
+	fields := make([]discordField, 0, len(msg.Facts))
+	for _, f := range msg.Facts {
+		// Discord rejects embeds with empty field values
+		if f.Value == "" {
+			continue
+		}
+		fields = append(fields, discordField{Name: f.Name, Value: truncate(f.Value, 1024), Inline: true})
+	}
+
+	return discordPayload{Embeds: []discordEmbed{{
+		Title:       truncate(msg.Title, 256),
+		URL:         msg.Link,
+		Description: truncate(msg.Message, 4096),
+		Color:       msg.Color,
+		Fields:      fields,
+		Timestamp:   msg.Time.UTC().Format(time.RFC3339),
+	}}}
+}
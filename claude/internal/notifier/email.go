@@ -0,0 +1,431 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+//go:embed templates/*
+var emailTemplatesFS embed.FS
+
+// emailFuncs are available in email templates
+var emailFuncs = map[string]any{
+	"outage":  formatOutage,
+	"latency": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+	// dashboard returns the dashboard link of a digest
+	"dashboard": func(payloads []Payload) string {
+		for _, p := range payloads {
+			if p.DashboardURL != "" {
+				return p.DashboardURL
+			}
+		}
+		return ""
+	},
+}
+
+var (
+	emailText = template.Must(template.New("").Funcs(emailFuncs).ParseFS(emailTemplatesFS, "templates/*.txt"))
+	emailHTML = htmltemplate.Must(htmltemplate.New("").Funcs(emailFuncs).ParseFS(emailTemplatesFS, "templates/*.html"))
+)
+
+// Email security modes
+const (
+	SecurityStartTLS = "starttls" // Upgrade a plain connection with STARTTLS, the default
+	SecurityTLS      = "tls"      // Implicit TLS from the start of the connection, usually port 465
+	SecurityNone     = "none"     // No encryption, only for relays on a trusted network
+)
+
+// DefaultSubjectPrefix starts the subject of every email
+const DefaultSubjectPrefix = "[Healthchecker]"
+
+// EmailNotifier sends state changes by email over SMTP. Each email has an
+// HTML and a plain text part. In digest mode the state changes within a
+// window are batched into a single email, so an outage that takes down many
+// checks at once sends one email instead of one per check.
+//
+// Options:
+//   - host: the SMTP server (required)
+//   - port: the SMTP port (default 587, or 465 with implicit TLS)
+//   - security: starttls (default), tls for implicit TLS, or none
+//   - username, password: log in with SMTP AUTH PLAIN
+//   - from: the sender address (required)
+//   - to: comma separated recipient addresses (required)
+//   - subject_prefix: starts every subject (default [Healthchecker])
+//   - digest: batch state changes within this window into one email, e.g. 5m
+//   - tls_skip_verify: do not verify the certificate of the server
+//   - timeout: limit for sending an email (default 10s)
+type EmailNotifier struct {
+	mu      sync.Mutex
+	batches map[string]*emailBatch // Pending digests by channel name
+	flushed bool                   // Digests are sent straight away once flushed on shutdown
+}
+
+// emailBatch is a digest collecting state changes until its window ends
+type emailBatch struct {
+	options emailOptions
+	events  []Event
+	timer   *time.Timer
+	sent    chan struct{} // Closed once the digest has been sent
+	err     error
+}
+
+// emailOptions are the parsed options of an email channel
+type emailOptions struct {
+	host          string
+	port          int
+	security      string
+	username      string
+	password      string
+	from          *mail.Address
+	to            []*mail.Address
+	subjectPrefix string
+	digest        time.Duration
+	skipVerify    bool
+	timeout       time.Duration
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier() *EmailNotifier {
+	return &EmailNotifier{batches: make(map[string]*emailBatch)}
+}
+
+// Type returns the channel type
+func (e *EmailNotifier) Type() models.ChannelType {
+	return models.ChannelTypeEmail
+}
+
+// Validate checks the server, addresses and digest window of the channel
+func (e *EmailNotifier) Validate(channel models.Channel) error {
+	_, err := parseEmailOptions(channel.Options)
+	return err
+}
+
+// Notify emails the event. In digest mode the event joins the pending digest
+// of the channel and Notify returns once the digest has been sent.
+func (e *EmailNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	options, err := parseEmailOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	if options.digest <= 0 || e.flushed {
+		e.mu.Unlock()
+		return sendEmail(options, []Event{event})
+	}
+
+	batch, ok := e.batches[channel.Name]
+	if !ok {
+		batch = &emailBatch{sent: make(chan struct{})}
+		batch.timer = time.AfterFunc(options.digest, func() { e.flush(channel.Name, batch) })
+		e.batches[channel.Name] = batch
+	}
+	batch.options = options
+	batch.events = append(batch.events, event)
+	e.mu.Unlock()
+
+	select {
+	case <-batch.sent:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush sends all pending digests straight away, and any later emails without
+// waiting for a digest, so that no state changes are lost on shutdown
+func (e *EmailNotifier) Flush() {
+	e.mu.Lock()
+	e.flushed = true
+	pending := make(map[string]*emailBatch, len(e.batches))
+	for name, batch := range e.batches {
+		pending[name] = batch
+	}
+	e.mu.Unlock()
+
+	for name, batch := range pending {
+		batch.timer.Stop()
+		e.flush(name, batch)
+	}
+}
+
+// flush sends a digest once its window has ended, unless it was already sent
+func (e *EmailNotifier) flush(name string, batch *emailBatch) {
+	e.mu.Lock()
+	if e.batches[name] != batch {
+		e.mu.Unlock()
+		return
+	}
+	delete(e.batches, name)
+	e.mu.Unlock()
+
+	batch.err = sendEmail(batch.options, batch.events)
+	close(batch.sent)
+}
+
+// parseEmailOptions validates the options of an email channel
+func parseEmailOptions(options map[string]string) (emailOptions, error) {
+	opts := emailOptions{
+		host:          options["host"],
+		security:      strings.ToLower(options["security"]),
+		username:      options["username"],
+		password:      options["password"],
+		subjectPrefix: DefaultSubjectPrefix,
+		timeout:       DefaultTimeout,
+	}
+
+	if opts.host == "" {
+		return opts, fmt.Errorf("host is required")
+	}
+	switch opts.security {
+	case "":
+		opts.security = SecurityStartTLS
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return opts, fmt.Errorf("invalid security %q, must be starttls, tls or none", opts.security)
+	}
+
+	opts.port = 587
+	if opts.security == SecurityTLS {
+		opts.port = 465
+	}
+	if raw := options["port"]; raw != "" {
+		port, err := strconv.Atoi(raw)
+		if err != nil || port <= 0 || port > 65535 {
+			return opts, fmt.Errorf("invalid port: %s", raw)
+		}
+		opts.port = port
+	}
+
+	if options["from"] == "" {
+		return opts, fmt.Errorf("from is required")
+	}
+	from, err := mail.ParseAddress(options["from"])
+	if err != nil {
+		return opts, fmt.Errorf("invalid from address: %w", err)
+	}
+	opts.from = from
+
+	if options["to"] == "" {
+		return opts, fmt.Errorf("to is required")
+	}
+	to, err := mail.ParseAddressList(options["to"])
+	if err != nil {
+		return opts, fmt.Errorf("invalid to address: %w", err)
+	}
+	opts.to = to
+
+	if prefix, ok := options["subject_prefix"]; ok {
+		opts.subjectPrefix = prefix
+	}
+	if raw := options["digest"]; raw != "" {
+		digest, err := time.ParseDuration(raw)
+		if err != nil || digest < 0 {
+			return opts, fmt.Errorf("invalid digest: %s", raw)
+		}
+		opts.digest = digest
+	}
+	if raw := options["tls_skip_verify"]; raw != "" {
+		skip, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid tls_skip_verify: %s", raw)
+		}
+		opts.skipVerify = skip
+	}
+	if raw := options["timeout"]; raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return opts, fmt.Errorf("invalid timeout: %s", raw)
+		}
+		opts.timeout = timeout
+	}
+
+	return opts, nil
+}
+
+// sendEmail composes the email for one or more events and sends it. A single
+// event gets a down or recovery email, several get a digest.
+func sendEmail(opts emailOptions, events []Event) error {
+	payloads := make([]Payload, len(events))
+	for i, event := range events {
+		payloads[i] = NewPayload(event)
+	}
+
+	subject, name, data := emailContent(opts, payloads)
+	var text, html bytes.Buffer
+	if err := emailText.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+	if err := emailHTML.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	message, err := composeEmail(opts, subject, text.Bytes(), html.Bytes())
+	if err != nil {
+		return err
+	}
+	return deliverEmail(opts, message)
+}
+
+// emailContent returns the subject, template name and template data of an email
+func emailContent(opts emailOptions, payloads []Payload) (string, string, any) {
+	subject := func(s string) string {
+		return strings.TrimSpace(opts.subjectPrefix + " " + s)
+	}
+
+	if len(payloads) > 1 {
+		down, recovered := 0, 0
+		for _, p := range payloads {
+			if p.Recovered() {
+				recovered++
+			} else {
+				down++
+			}
+		}
+		return subject(fmt.Sprintf("%d state changes: %d down, %d recovered", len(payloads), down, recovered)), "digest", payloads
+	}
+
+	p := payloads[0]
+	if p.Recovered() {
+		s := fmt.Sprintf("RECOVERED: %s %s check", p.Host, p.Check)
+		if p.Outage > 0 {
+			s += " after " + formatOutage(p.Outage)
+		}
+		return subject(s), "recovery", p
+	}
+	return subject(fmt.Sprintf("%s: %s %s check", p.State, p.Host, p.Check)), "down", p
+}
+
+// composeEmail builds a multipart/alternative message with a plain text and an HTML part
+func composeEmail(opts emailOptions, subject string, text, html []byte) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	to := make([]string, len(opts.to))
+	for i, addr := range opts.to {
+		to[i] = addr.String()
+	}
+
+	id := make([]byte, 12)
+	rand.Read(id)
+	domain := opts.from.Address[strings.LastIndex(opts.from.Address, "@")+1:]
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", opts.from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n", writer.Boundary())
+	fmt.Fprintf(&msg, "\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// deliverEmail sends a message to the SMTP server of the channel
+func deliverEmail(opts emailOptions, message []byte) error {
+	addr := net.JoinHostPort(opts.host, strconv.Itoa(opts.port))
+	tlsConfig := &tls.Config{ServerName: opts.host, InsecureSkipVerify: opts.skipVerify}
+	dialer := &net.Dialer{Timeout: opts.timeout}
+
+	var conn net.Conn
+	var err error
+	if opts.security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(opts.timeout))
+
+	client, err := smtp.NewClient(conn, opts.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if opts.security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("server %s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if opts.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", opts.username, opts.password, opts.host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(opts.from.Address); err != nil {
+		return fmt.Errorf("sender rejected: %w", err)
+	}
+	for _, to := range opts.to {
+		if err := client.Rcpt(to.Address); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", to.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,386 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// smtpStandIn is a minimal SMTP server that records the emails it receives
+type smtpStandIn struct {
+	listener net.Listener
+	tls      *tls.Config // Offered with STARTTLS when set
+	mu       sync.Mutex
+	emails   []receivedEmail
+}
+
+// receivedEmail is an email accepted by the stand-in
+type receivedEmail struct {
+	auth    string // Decoded AUTH PLAIN credentials
+	tls     bool   // Whether the session was encrypted
+	from    string
+	to      []string
+	message *mail.Message
+	text    string
+	html    string
+}
+
+// newSMTPStandIn starts a stand-in on a local port. With security tls the
+// whole connection is encrypted, with starttls the server offers STARTTLS.
+func newSMTPStandIn(t *testing.T, security string) (*smtpStandIn, map[string]string) {
+	t.Helper()
+
+	// Borrow the self-signed certificate of an httptest server
+	certServer := httptest.NewTLSServer(nil)
+	tlsConfig := &tls.Config{Certificates: certServer.TLS.Certificates}
+	certServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &smtpStandIn{listener: listener}
+	switch security {
+	case SecurityTLS:
+		s.listener = tls.NewListener(listener, tlsConfig)
+	case SecurityStartTLS:
+		s.tls = tlsConfig
+	}
+	t.Cleanup(func() { s.listener.Close() })
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, security == SecurityTLS)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return s, map[string]string{
+		"host":            host,
+		"port":            port,
+		"security":        security,
+		"tls_skip_verify": "true",
+		"from":            "Healthchecker <alerts@example.com>",
+		"to":              "ops@example.com, oncall@example.com",
+	}
+}
+
+// serve handles a single SMTP session
+func (s *smtpStandIn) serve(conn net.Conn, encrypted bool) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	var email receivedEmail
+	email.tls = encrypted
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO":
+			fmt.Fprintf(conn, "250-localhost\r\n")
+			if s.tls != nil && !email.tls {
+				fmt.Fprintf(conn, "250-STARTTLS\r\n")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			email.tls = true
+		case "AUTH":
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			email.auth = strings.ReplaceAll(string(decoded), "\x00", ":")
+			reply("235 Authenticated")
+		case "MAIL":
+			email.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			email.to = append(email.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				l, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.record(email, data.String())
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// record parses a received message into its text and HTML parts
+func (s *smtpStandIn) record(email receivedEmail, data string) {
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err == nil {
+		email.message = msg
+		_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		parts := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err != nil {
+				break
+			}
+			body, _ := io.ReadAll(quotedprintable.NewReader(part))
+			if strings.HasPrefix(part.Header.Get("Content-Type"), "text/html") {
+				email.html = string(body)
+			} else {
+				email.text = string(body)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emails = append(s.emails, email)
+}
+
+func (s *smtpStandIn) received() []receivedEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedEmail(nil), s.emails...)
+}
+
+func subject(email receivedEmail) string {
+	decoded, _ := new(mime.WordDecoder).DecodeHeader(email.message.Header.Get("Subject"))
+	return decoded
+}
+
+func TestEmailNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		security    string
+		username    string
+		event       Event
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+	}{
+		{
+			name:        "down over starttls with auth",
+			security:    SecurityStartTLS,
+			username:    "monitor",
+			event:       testEvent(),
+			wantSubject: "[Healthchecker] DOWN: web-1 http check",
+			wantText:    []string{"web-1 http check is DOWN", "State:    UP -> DOWN", "Latency:  1.5s", `status "503"`},
+			wantHTML:    []string{"web-1 http check is DOWN", "<td>UP &rarr; DOWN</td>", "status &#34;503&#34;"},
+		},
+		{
+			name:        "recovery over implicit tls",
+			security:    SecurityTLS,
+			event:       recoveryEvent(),
+			wantSubject: "[Healthchecker] RECOVERED: web-1 http check after 5m30s",
+			wantText:    []string{"recovered after 5m30s", "Outage:   5m30s", "Dashboard: https://status.example.com"},
+			wantHTML:    []string{"recovered after 5m30s", `<a href="https://status.example.com">Open dashboard</a>`, "status &lt;200&gt;"},
+		},
+		{
+			name:        "plain relay",
+			security:    SecurityNone,
+			event:       testEvent(),
+			wantSubject: "[Healthchecker] DOWN: web-1 http check",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, options := newSMTPStandIn(t, tt.security)
+			if tt.username != "" {
+				options["username"] = tt.username
+				options["password"] = "s3cret"
+			}
+			channel := models.Channel{Name: "mail", Type: models.ChannelTypeEmail, Options: options}
+
+			notifier := NewEmailNotifier()
+			if err := notifier.Validate(channel); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if err := notifier.Notify(context.Background(), channel, tt.event); err != nil {
+				t.Fatalf("Notify failed: %v", err)
+			}
+
+			emails := server.received()
+			if len(emails) != 1 {
+				t.Fatalf("Expected 1 email, got %d", len(emails))
+			}
+			email := emails[0]
+
+			if tt.security != SecurityNone && !email.tls {
+				t.Error("Expected the session to be encrypted")
+			}
+			if tt.username != "" && email.auth != ":monitor:s3cret" {
+				t.Errorf("Expected AUTH PLAIN as monitor, got %q", email.auth)
+			}
+			if email.from != "alerts@example.com" {
+				t.Errorf("Expected sender alerts@example.com, got %s", email.from)
+			}
+			if strings.Join(email.to, ",") != "ops@example.com,oncall@example.com" {
+				t.Errorf("Expected both recipients, got %v", email.to)
+			}
+			if got := subject(email); got != tt.wantSubject {
+				t.Errorf("Expected subject %q, got %q", tt.wantSubject, got)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(email.text, want) {
+					t.Errorf("Expected text part to contain %q, got:\n%s", want, email.text)
+				}
+			}
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(email.html, want) {
+					t.Errorf("Expected HTML part to contain %q, got:\n%s", want, email.html)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailNotifierDigest(t *testing.T) {
+	server, options := newSMTPStandIn(t, SecurityNone)
+	options["digest"] = "200ms"
+	channel := models.Channel{Name: "mail", Type: models.ChannelTypeEmail, Options: options}
+	notifier := NewEmailNotifier()
+
+	// A network-wide outage: many checks go down at once, and one recovers
+	var wg sync.WaitGroup
+	errs := make(chan error, 11)
+	for i := 0; i < 10; i++ {
+		event := testEvent()
+		event.Host.Name = "web-" + strconv.Itoa(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- notifier.Notify(context.Background(), channel, event)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs <- notifier.Notify(context.Background(), channel, recoveryEvent())
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	emails := server.received()
+	if len(emails) != 1 {
+		t.Fatalf("Expected 1 digest email, got %d", len(emails))
+	}
+	if got, want := subject(emails[0]), "[Healthchecker] 11 state changes: 10 down, 1 recovered"; got != want {
+		t.Errorf("Expected subject %q, got %q", want, got)
+	}
+	for _, want := range []string{"11 checks changed state", "DOWN  web-9 http check", "RECOVERED  web-1 http check (DOWN -> UP, down for 5m30s)"} {
+		if !strings.Contains(emails[0].text, want) {
+			t.Errorf("Expected digest to contain %q, got:\n%s", want, emails[0].text)
+		}
+	}
+
+	// Pending digests are sent straight away when flushed on shutdown
+	hourly := models.Channel{Name: "hourly", Type: models.ChannelTypeEmail, Options: make(map[string]string)}
+	for k, v := range options {
+		hourly.Options[k] = v
+	}
+	hourly.Options["digest"] = "1h"
+	done := make(chan error)
+	go func() { done <- notifier.Notify(context.Background(), hourly, testEvent()) }()
+	time.Sleep(50 * time.Millisecond)
+	notifier.Flush()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the pending digest to be sent on flush")
+	}
+	if got := len(server.received()); got != 2 {
+		t.Errorf("Expected 2 emails after flushing, got %d", got)
+	}
+}
+
+func TestEmailNotifierValidate(t *testing.T) {
+	valid := map[string]string{"host": "smtp.example.com", "from": "alerts@example.com", "to": "ops@example.com"}
+	with := func(key, value string) map[string]string {
+		options := make(map[string]string)
+		for k, v := range valid {
+			options[k] = v
+		}
+		options[key] = value
+		return options
+	}
+
+	tests := []struct {
+		name    string
+		options map[string]string
+		wantErr string
+	}{
+		{name: "valid", options: valid},
+		{name: "missing host", options: with("host", ""), wantErr: "host is required"},
+		{name: "missing recipients", options: with("to", ""), wantErr: "to is required"},
+		{name: "bad recipient", options: with("to", "ops@example.com, not an address"), wantErr: "invalid to address"},
+		{name: "bad security", options: with("security", "ssl"), wantErr: "invalid security"},
+		{name: "bad port", options: with("port", "smtp"), wantErr: "invalid port"},
+		{name: "bad digest", options: with("digest", "soon"), wantErr: "invalid digest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewEmailNotifier().Validate(models.Channel{Name: "mail", Type: models.ChannelTypeEmail, Options: tt.options})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
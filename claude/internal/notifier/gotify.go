@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultGotifyPriority is the priority of failing checks, high enough for
+// the Gotify Android app to show a pop-up
+const DefaultGotifyPriority = 8
+
+// GotifyNotifier sends state changes to a self-hosted Gotify server as
+// messages of an application.
+//
+// Options:
+//   - url: the URL of the Gotify server (required)
+//   - token: the token of the application (required)
+//   - priority: the priority of failing checks, 0 to 10 (default: 8).
+//     Recoveries get the default priority of the application
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type GotifyNotifier struct {
+	httpClient *http.Client
+}
+
+// NewGotifyNotifier creates a new Gotify notifier
+func NewGotifyNotifier() *GotifyNotifier {
+	return &GotifyNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (g *GotifyNotifier) Type() models.ChannelType {
+	return models.ChannelTypeGotify
+}
+
+// Validate checks the server URL, token, priority and retry options of the channel
+func (g *GotifyNotifier) Validate(channel models.Channel) error {
+	_, _, err := parseGotifyOptions(channel.Options)
+	return err
+}
+
+type gotifyPayload struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority *int           `json:"priority,omitempty"` // Unset uses the default of the application
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+// Notify sends the event as a message of the application
+func (g *GotifyNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	priority, policy, err := parseGotifyOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	msg := newPushMessage(event)
+	payload := gotifyPayload{Title: msg.Title, Message: msg.Body}
+	if msg.Failing {
+		payload.Priority = &priority
+	}
+	if msg.Link != "" {
+		// Opens the dashboard when the notification is tapped in the Android app
+		payload.Extras = map[string]any{"client::notification": map[string]any{"click": map[string]string{"url": msg.Link}}}
+	}
+
+	header := http.Header{"X-Gotify-Key": {channel.Options["token"]}}
+	return sendJSON(ctx, g.httpClient, policy, strings.TrimSuffix(channel.Options["url"], "/")+"/message", header, payload)
+}
+
+// parseGotifyOptions reads the priority of failing checks and the retry
+// options of a Gotify channel
+func parseGotifyOptions(options map[string]string) (int, retryPolicy, error) {
+	if err := validateURL(options["url"]); err != nil {
+		return 0, retryPolicy{}, err
+	}
+	if options["token"] == "" {
+		return 0, retryPolicy{}, fmt.Errorf("token is required")
+	}
+
+	priority := DefaultGotifyPriority
+	if raw := options["priority"]; raw != "" {
+		p, err := strconv.Atoi(raw)
+		if err != nil || p < 0 || p > 10 {
+			return 0, retryPolicy{}, fmt.Errorf("invalid priority: %s", raw)
+		}
+		priority = p
+	}
+
+	policy, err := parseRetryPolicy(options)
+	return priority, policy, err
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// HealthchecksNotifier pings healthcheck.io with the result of a check
+type HealthchecksNotifier struct {
+	httpClient *http.Client
+}
+
+// NewHealthchecksNotifier creates a new healthcheck.io notifier
+func NewHealthchecksNotifier() *HealthchecksNotifier {
+	return &HealthchecksNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the channel type
+func (h *HealthchecksNotifier) Type() models.ChannelType {
+	return models.ChannelTypeHealthchecks
+}
+
+// Validate checks that the channel has a ping URL
+func (h *HealthchecksNotifier) Validate(channel models.Channel) error {
+	if channel.Options["url"] == "" {
+		return fmt.Errorf("url is required")
+	}
+	return nil
+}
+
+// Notify pings the success URL while the check is up, in maintenance or
+// unreachable, and the failure URL while it is down or flapping. The level
+// and message of the result are shown in the event log of the check.
+func (h *HealthchecksNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	url := channel.Options["url"]
+	if url == "" {
+		return nil // Healthcheck.io not configured for this check, skip
+	}
+
+	switch event.Status.State {
+	case models.StateUp, models.StateMaintenance, models.StateUnreachable:
+		// Keep pinging during maintenance and while a parent is down so
+		// healthchecks.io does not raise its own alert for missing pings.
+		// An outage of the parent is reported by the parent's own checks.
+	case models.StateDown, models.StateFlapping:
+		url = fmt.Sprintf("%s/fail", url)
+	default:
+		return nil
+	}
+
+	message := fmt.Sprintf("%s: %s", event.Result.Level, event.Result.Message)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to ping healthcheck.io: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("healthcheck.io returned error status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
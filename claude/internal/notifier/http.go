@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Default retry behaviour of notifications sent over HTTP
+const (
+	DefaultRetries    = 3
+	DefaultRetryDelay = time.Second
+	DefaultTimeout    = 10 * time.Second
+)
+
+// retryPolicy is how often and how soon a failed notification is resent
+type retryPolicy struct {
+	retries int           // Attempts after the first one
+	delay   time.Duration // Wait before the first retry, doubled for every further retry
+	timeout time.Duration // Limit for a single attempt
+}
+
+// parseRetryPolicy reads the retries, retry_delay and timeout options of a channel
+func parseRetryPolicy(options map[string]string) (retryPolicy, error) {
+	policy := retryPolicy{retries: DefaultRetries, delay: DefaultRetryDelay, timeout: DefaultTimeout}
+
+	if raw := options["retries"]; raw != "" {
+		retries, err := strconv.Atoi(raw)
+		if err != nil || retries < 0 {
+			return policy, fmt.Errorf("invalid retries: %s", raw)
+		}
+		policy.retries = retries
+	}
+	if raw := options["retry_delay"]; raw != "" {
+		delay, err := time.ParseDuration(raw)
+		if err != nil || delay < 0 {
+			return policy, fmt.Errorf("invalid retry_delay: %s", raw)
+		}
+		policy.delay = delay
+	}
+	if raw := options["timeout"]; raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return policy, fmt.Errorf("invalid timeout: %s", raw)
+		}
+		policy.timeout = timeout
+	}
+
+	return policy, nil
+}
+
+// validateURL checks that a channel URL is set and is an HTTP or HTTPS URL
+func validateURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url: %s", raw)
+	}
+	return nil
+}
+
+// post sends a request built by newRequest, retrying with exponential backoff
+// when the request fails, times out or gets a 429 or 5xx response. Other
+// error responses are not retried as resending would not change them.
+func post(ctx context.Context, client *http.Client, policy retryPolicy, newRequest func(ctx context.Context) (*http.Request, error)) error {
+	delay := policy.delay
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = attemptRequest(ctx, client, policy.timeout, newRequest)
+		if err == nil || !retry || attempt >= policy.retries {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (%v)", err, ctx.Err())
+		}
+		delay *= 2
+	}
+
+	if err != nil && policy.retries > 0 {
+		return fmt.Errorf("giving up after %d attempts: %w", policy.retries+1, err)
+	}
+	return err
+}
+
+// sendJSON posts a message encoded as JSON to url with the given extra
+// headers, retrying as post does
+func sendJSON(ctx context.Context, client *http.Client, policy retryPolicy, url string, header http.Header, message any) error {
+	// Messages contain <, > and & in Slack links and check messages, which
+	// are easier to read in request logs without HTML escaping
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(message); err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	body := buf.Bytes()
+
+	return post(ctx, client, policy, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
+
+// attemptRequest sends a request once and reports whether it is worth retrying
+func attemptRequest(ctx context.Context, client *http.Client, timeout time.Duration, newRequest func(ctx context.Context) (*http.Request, error)) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := newRequest(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("unexpected status code: %d %s", resp.StatusCode, bytes.TrimSpace(body))
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+	}
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	return false, nil
+}
@@ -0,0 +1,213 @@
+package notifier
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// incidentAction is what an incident notifier does for an event
+type incidentAction int
+
+const (
+	incidentNone    incidentAction = iota
+	incidentTrigger                // The check is down or flapping: open or update the incident
+	incidentResolve                // The check recovered: resolve the incident
+)
+
+// incidentActionFor returns what an incident notifier does for an event.
+// Incidents are opened while a check is down or flapping and resolved when
+// it recovers, other state changes do not touch them.
+func incidentActionFor(p Payload) incidentAction {
+	switch {
+	case p.State == models.StateDown || p.State == models.StateFlapping:
+		return incidentTrigger
+	case p.Recovered():
+		return incidentResolve
+	}
+	return incidentNone
+}
+
+// incidentKey identifies the incident of a check as "host/type#index", the
+// index telling apart checks of the same type on a host. It is the same for
+// every event of the check, so later triggers update the open incident and a
+// recovery resolves it.
+func incidentKey(event Event) string {
+	return fmt.Sprintf("%s/%s#%d", event.Host.Name, event.Check.Type, event.Result.Index)
+}
+
+// openIncidents remembers the incidents a notifier opened, by channel and
+// check, so the incident of a check is resolved when the check is next up,
+// whatever states it went through in between
+type openIncidents struct {
+	mu   sync.Mutex
+	open map[string]openIncident // By channel name and models.CheckID
+}
+
+// openIncident is the check an incident was opened for
+type openIncident struct {
+	checkID     string
+	fingerprint string // models.CheckFingerprint of the check
+}
+
+func newOpenIncidents() *openIncidents {
+	return &openIncidents{open: make(map[string]openIncident)}
+}
+
+// action returns what a notifier does for an event on a channel. Triggers
+// are recorded, and an event that brings the check up resolves the incident
+// if one was opened for it or the check recovered from down or flapping. An
+// incident opened for a check that was since replaced is not resolved by
+// the check that took its place.
+func (o *openIncidents) action(channel models.Channel, event Event) incidentAction {
+	incident := openIncident{
+		checkID:     models.CheckID(event.Host.Name, event.Result.Index),
+		fingerprint: models.CheckFingerprint(event.Check),
+	}
+	id := channel.Name + "\x00" + incident.checkID
+	p := NewPayload(event)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	last, open := o.open[id]
+	if open && last.fingerprint != incident.fingerprint {
+		delete(o.open, id)
+		open = false
+	}
+	action := incidentActionFor(p)
+	switch {
+	case action == incidentTrigger:
+		o.open[id] = incident
+		return incidentTrigger
+	case p.State == models.StateUp && (open || action == incidentResolve):
+		delete(o.open, id)
+		return incidentResolve
+	}
+	return incidentNone
+}
+
+// prune forgets the incidents of checks that were removed or replaced
+func (o *openIncidents) prune(fingerprints map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for id, incident := range o.open {
+		if fingerprint, ok := fingerprints[incident.checkID]; !ok || fingerprint != incident.fingerprint {
+			delete(o.open, id)
+		}
+	}
+}
+
+// incidentSeverity returns the key severity and priority options are looked
+// up by: FLAPPING for a flapping check, otherwise the level of the result
+// that took it down, CRITICAL or UNKNOWN
+func incidentSeverity(p Payload) string {
+	if p.State == models.StateFlapping {
+		return string(models.StateFlapping)
+	}
+	if p.Level == models.LevelUnknown {
+		return string(models.LevelUnknown)
+	}
+	return string(models.LevelCritical)
+}
+
+// incidentSummary is the one line title of an incident
+func incidentSummary(p Payload) string {
+	summary := fmt.Sprintf("%s %s check is %s", p.Host, p.Check, p.State)
+	if p.Message != "" {
+		summary += ": " + p.Message
+	}
+	return summary
+}
+
+// incidentDetails are the custom details attached to an incident
+func incidentDetails(p Payload) map[string]string {
+	details := map[string]string{
+		"host":           p.Host,
+		"check":          string(p.Check),
+		"state":          string(p.State),
+		"previous_state": string(p.Previous),
+		"level":          string(p.Level),
+		"message":        p.Message,
+		"latency_ms":     fmt.Sprint(p.LatencyMS),
+		"checked_at":     p.Timestamp.Format(time.RFC3339),
+	}
+	if p.Address != "" {
+		details["address"] = p.Address
+	}
+	if !p.Since.IsZero() {
+		details["since"] = p.Since.Format(time.RFC3339)
+	}
+	if p.Reason != "" {
+		details["reason"] = p.Reason
+	}
+	if len(p.Tags) > 0 {
+		details["tags"] = strings.Join(p.Tags, ", ")
+	}
+	if p.DashboardURL != "" {
+		details["dashboard_url"] = p.DashboardURL
+	}
+	return details
+}
+
+// incidentOptions are the options of an incident channel
+type incidentOptions struct {
+	key        string            // Routing or API key
+	url        string            // API endpoint
+	severities map[string]string // Severity or priority by incidentSeverity key
+	policy     retryPolicy
+}
+
+// parseIncidentOptions reads the options of an incident channel: the key in
+// keyOption, url defaulting to defaultURL, severity overrides under prefix
+// and the retry options
+func parseIncidentOptions(options map[string]string, keyOption, defaultURL, prefix string, defaults map[string]string, allowed []string) (incidentOptions, error) {
+	parsed := incidentOptions{key: options[keyOption], url: options["url"]}
+	if parsed.key == "" {
+		return parsed, fmt.Errorf("%s is required", keyOption)
+	}
+	if parsed.url == "" {
+		parsed.url = defaultURL
+	}
+	if err := validateURL(parsed.url); err != nil {
+		return parsed, err
+	}
+
+	var err error
+	if parsed.severities, err = parseSeverityMap(options, prefix, defaults, allowed); err != nil {
+		return parsed, err
+	}
+	if parsed.policy, err = parseRetryPolicy(options); err != nil {
+		return parsed, err
+	}
+	return parsed, nil
+}
+
+// parseSeverityMap reads the <prefix>.<key> options of a channel that
+// override the default severities, e.g. severity.UNKNOWN: warning. Keys are
+// CRITICAL, UNKNOWN and FLAPPING, values must be in allowed.
+func parseSeverityMap(options map[string]string, prefix string, defaults map[string]string, allowed []string) (map[string]string, error) {
+	severities := make(map[string]string, len(defaults))
+	for key, value := range defaults {
+		severities[key] = value
+	}
+
+	for option, value := range options {
+		key, ok := strings.CutPrefix(option, prefix+".")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+		if _, known := defaults[key]; !known {
+			return nil, fmt.Errorf("invalid %s: %s is not CRITICAL, UNKNOWN or FLAPPING", option, key)
+		}
+		if !slices.Contains(allowed, value) {
+			return nil, fmt.Errorf("invalid %s: %s (must be one of %s)", option, value, strings.Join(allowed, ", "))
+		}
+		severities[key] = value
+	}
+	return severities, nil
+}
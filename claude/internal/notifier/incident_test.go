@@ -0,0 +1,231 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestPagerDutyNotifier(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusAccepted)
+	notifier := NewPagerDutyNotifier()
+	channel := models.Channel{Name: "pd", Type: models.ChannelTypePagerDuty, Options: map[string]string{
+		"routing_key":      "R0UT1NGK3Y",
+		"url":              server.URL,
+		"severity.UNKNOWN": "warning",
+	}}
+
+	down := testEvent()
+	down.DashboardURL = "https://status.example.com"
+	unknown := testEvent()
+	unknown.Result.Level = models.LevelUnknown
+	flapping := testEvent()
+	flapping.Status.State = models.StateFlapping
+	// Another http check of the host coming up for the first time
+	firstUp := testEvent()
+	firstUp.Result.Index = 1
+	firstUp.Status = models.Status{State: models.StateUp, Previous: models.StatePending}
+
+	for _, event := range []Event{down, unknown, flapping, firstUp, recoveryEvent()} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 4 {
+		t.Fatalf("Expected 4 requests, the first UP sends nothing, got %d", len(got))
+	}
+
+	var events []pagerDutyEvent
+	for _, r := range got {
+		var event pagerDutyEvent
+		if err := json.Unmarshal(r.body, &event); err != nil {
+			t.Fatalf("Expected a JSON body, got %s", r.body)
+		}
+		if event.RoutingKey != "R0UT1NGK3Y" || event.DedupKey != "web-1/http#0" {
+			t.Errorf("Expected routing key and dedup key web-1/http#0, got %s and %s", event.RoutingKey, event.DedupKey)
+		}
+		events = append(events, event)
+	}
+
+	trigger := events[0]
+	if trigger.EventAction != "trigger" || trigger.Payload == nil {
+		t.Fatalf("Expected a trigger with a payload, got %s", got[0].body)
+	}
+	if trigger.Payload.Severity != "critical" || trigger.Payload.Source != "10.0.0.1" || trigger.Payload.Class != "http" {
+		t.Errorf("Unexpected payload %+v", trigger.Payload)
+	}
+	if trigger.Payload.Summary != `web-1 http check is DOWN: status "503"` {
+		t.Errorf("Unexpected summary %q", trigger.Payload.Summary)
+	}
+	if details := trigger.Payload.CustomDetails; details["message"] != `status "503"` || details["latency_ms"] != "1500" || details["checked_at"] != "2023-11-14T22:13:20Z" {
+		t.Errorf("Unexpected custom details %v", details)
+	}
+	if len(trigger.Links) != 1 || trigger.Links[0].Href != "https://status.example.com" {
+		t.Errorf("Expected a dashboard link, got %v", trigger.Links)
+	}
+
+	if severity := events[1].Payload.Severity; severity != "warning" {
+		t.Errorf("Expected the severity.UNKNOWN option to give warning, got %s", severity)
+	}
+	if severity := events[2].Payload.Severity; severity != "warning" {
+		t.Errorf("Expected FLAPPING to give warning, got %s", severity)
+	}
+
+	if resolve := events[3]; resolve.EventAction != "resolve" || resolve.Payload != nil {
+		t.Errorf("Expected a resolve without a payload, got %s", got[3].body)
+	}
+}
+
+func TestOpsgenieNotifier(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusAccepted)
+	notifier := NewOpsgenieNotifier()
+	channel := models.Channel{Name: "og", Type: models.ChannelTypeOpsgenie, Options: map[string]string{
+		"api_key":    "k3y",
+		"url":        server.URL,
+		"responders": "ops, web",
+	}}
+
+	for _, event := range []Event{testEvent(), recoveryEvent()} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	for _, r := range got {
+		if auth := r.header.Get("Authorization"); auth != "GenieKey k3y" {
+			t.Errorf("Expected Authorization GenieKey k3y, got %q", auth)
+		}
+	}
+
+	if got[0].path != "/v2/alerts" {
+		t.Errorf("Expected the alert to be created at /v2/alerts, got %s", got[0].path)
+	}
+	var alert opsgenieAlert
+	if err := json.Unmarshal(got[0].body, &alert); err != nil {
+		t.Fatalf("Expected a JSON body, got %s", got[0].body)
+	}
+	if alert.Alias != "web-1/http#0" || alert.Priority != "P1" || alert.Entity != "web-1" {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+	if alert.Details["message"] != `status "503"` || alert.Details["tags"] != "prod" {
+		t.Errorf("Unexpected details %v", alert.Details)
+	}
+	if len(alert.Responders) != 2 || alert.Responders[1] != (opsgenieResponder{Name: "web", Type: "team"}) {
+		t.Errorf("Unexpected responders %v", alert.Responders)
+	}
+
+	if got[1].path != "/v2/alerts/web-1%2Fhttp%230/close?identifierType=alias" {
+		t.Errorf("Expected the alert to be closed by alias, got %s", got[1].path)
+	}
+	var closed opsgenieClose
+	if err := json.Unmarshal(got[1].body, &closed); err != nil {
+		t.Fatalf("Expected a JSON body, got %s", got[1].body)
+	}
+	if closed.Note != "web-1 http check recovered after 5m30s" {
+		t.Errorf("Unexpected note %q", closed.Note)
+	}
+}
+
+func TestIncidentResolvedWhenUp(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusAccepted)
+	notifier := NewPagerDutyNotifier()
+	channel := models.Channel{Name: "pd", Type: models.ChannelTypePagerDuty, Options: map[string]string{
+		"routing_key": "R0UT1NGK3Y",
+		"url":         server.URL,
+	}}
+
+	// The check goes down, then unreachable behind its parent, then up
+	unreachable := testEvent()
+	unreachable.Status = models.Status{State: models.StateUnreachable, Previous: models.StateDown}
+	up := recoveryEvent()
+	up.Status.Previous = models.StateUnreachable
+	other := recoveryEvent()
+	other.Result.Index = 1
+
+	for _, event := range []Event{testEvent(), unreachable, up, up, other} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 3 {
+		t.Fatalf("Expected a trigger and a resolve of web-1/http#0 and a resolve of web-1/http#1, got %d requests", len(got))
+	}
+	want := []struct{ action, key string }{{"trigger", "web-1/http#0"}, {"resolve", "web-1/http#0"}, {"resolve", "web-1/http#1"}}
+	for i, r := range got {
+		var event pagerDutyEvent
+		if err := json.Unmarshal(r.body, &event); err != nil {
+			t.Fatalf("Expected a JSON body, got %s", r.body)
+		}
+		if event.EventAction != want[i].action || event.DedupKey != want[i].key {
+			t.Errorf("Expected request %d to %s %s, got %s %s", i, want[i].action, want[i].key, event.EventAction, event.DedupKey)
+		}
+	}
+}
+
+func TestIncidentForgetsReplacedChecks(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusAccepted)
+	notifier := NewPagerDutyNotifier()
+	channel := models.Channel{Name: "pd", Type: models.ChannelTypePagerDuty, Options: map[string]string{
+		"routing_key": "R0UT1NGK3Y",
+		"url":         server.URL,
+	}}
+
+	// A check with other options takes the place of the check that went down
+	replaced := recoveryEvent()
+	replaced.Status.Previous = models.StatePending
+	replaced.Check.Options = map[string]string{"url": "https://web-1/"}
+	for _, event := range []Event{testEvent(), replaced} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+	if got := requests(); len(got) != 1 {
+		t.Errorf("Expected the new check coming up not to resolve the incident of the old one, got %d requests", len(got))
+	}
+
+	if err := notifier.Notify(context.Background(), channel, testEvent()); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	notifier.Prune(map[string]string{})
+	if len(notifier.incidents.open) != 0 {
+		t.Errorf("Expected the incidents of removed checks to be forgotten, got %v", notifier.incidents.open)
+	}
+}
+
+func TestIncidentNotifiersValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier Notifier
+		options  map[string]string
+		wantErr  bool
+	}{
+		{"pagerduty defaults", NewPagerDutyNotifier(), map[string]string{"routing_key": "key"}, false},
+		{"pagerduty without key", NewPagerDutyNotifier(), map[string]string{}, true},
+		{"pagerduty bad severity", NewPagerDutyNotifier(), map[string]string{"routing_key": "key", "severity.CRITICAL": "P1"}, true},
+		{"pagerduty unknown level", NewPagerDutyNotifier(), map[string]string{"routing_key": "key", "severity.WARNING": "info"}, true},
+		{"opsgenie eu", NewOpsgenieNotifier(), map[string]string{"api_key": "key", "url": "https://api.eu.opsgenie.com", "priority.flapping": "P4"}, false},
+		{"opsgenie without key", NewOpsgenieNotifier(), map[string]string{"url": "https://api.eu.opsgenie.com"}, true},
+		{"opsgenie bad priority", NewOpsgenieNotifier(), map[string]string{"api_key": "key", "priority.CRITICAL": "critical"}, true},
+		{"opsgenie bad url", NewOpsgenieNotifier(), map[string]string{"api_key": "key", "url": "api.opsgenie.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.notifier.(Validator).Validate(models.Channel{Name: tt.name, Type: tt.notifier.Type(), Options: tt.options})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
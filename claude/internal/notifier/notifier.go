@@ -0,0 +1,325 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// Event is a check result together with the confirmed status of the check
+// after that result
+type Event struct {
+	Host         models.Host
+	Check        models.Check
+	Result       models.CheckResult
+	Status       models.Status
+	DashboardURL string // Where notifications link to for details
+}
+
+// Notifier is the interface that all notification channels must implement.
+// Like checkers, notifiers get the options of the channel with every call.
+type Notifier interface {
+	Notify(ctx context.Context, channel models.Channel, event Event) error
+	Type() models.ChannelType
+}
+
+// Validator is implemented by notifiers that can check the options of a
+// channel before anything is sent to it
+type Validator interface {
+	Validate(channel models.Channel) error
+}
+
+// Flusher is implemented by notifiers that hold notifications back, such as
+// email digests, and can send them straight away on shutdown
+type Flusher interface {
+	Flush()
+}
+
+// Pruner is implemented by notifiers that remember checks between
+// notifications, such as the incidents they opened. Prune is given the
+// models.CheckFingerprint of every configured check by models.CheckID and
+// forgets checks that were removed or replaced.
+type Pruner interface {
+	Prune(fingerprints map[string]string)
+}
+
+// Registry holds all registered notifiers
+type Registry struct {
+	notifiers map[models.ChannelType]Notifier
+}
+
+// NewRegistry creates a new notifier registry
+func NewRegistry() *Registry {
+	return &Registry{
+		notifiers: make(map[models.ChannelType]Notifier),
+	}
+}
+
+// Register registers a notifier
+func (r *Registry) Register(notifier Notifier) {
+	r.notifiers[notifier.Type()] = notifier
+}
+
+// Get retrieves a notifier by channel type
+func (r *Registry) Get(channelType models.ChannelType) (Notifier, error) {
+	notifier, ok := r.notifiers[channelType]
+	if !ok {
+		return nil, fmt.Errorf("no notifier registered for type: %s", channelType)
+	}
+	return notifier, nil
+}
+
+// GetAll returns all registered notifiers
+func (r *Registry) GetAll() map[models.ChannelType]Notifier {
+	return r.notifiers
+}
+
+// Validate checks that every channel has a registered notifier and valid options
+func (r *Registry) Validate(channels []models.Channel) error {
+	for _, channel := range channels {
+		notifier, err := r.Get(channel.Type)
+		if err != nil {
+			return fmt.Errorf("notification channel %s: %w", channel.Name, err)
+		}
+		if v, ok := notifier.(Validator); ok {
+			if err := v.Validate(channel); err != nil {
+				return fmt.Errorf("notification channel %s: %w", channel.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ConfigSource provides the dispatcher with the current configuration
+type ConfigSource interface {
+	GetConfig() *models.Config
+}
+
+// Dispatcher sends check results to notification channels. Notifications are
+// sent in the background so slow channels do not hold up checks.
+type Dispatcher struct {
+	registry *Registry
+	source   ConfigSource
+	sends    sync.WaitGroup
+
+	mu       sync.Mutex
+	notified map[string]notified // By models.CheckID
+}
+
+// notified is the last state of a check that its channels were told about
+type notified struct {
+	fingerprint string // models.CheckFingerprint of the check
+	state       models.State
+	since       time.Time
+}
+
+// NewDispatcher creates a dispatcher for the channels of the configuration
+func NewDispatcher(registry *Registry, source ConfigSource) *Dispatcher {
+	return &Dispatcher{registry: registry, source: source, notified: make(map[string]notified)}
+}
+
+// Handle receives every check result, it has the signature of a scheduler
+// result handler. Checks with a healthcheck.io URL ping it after every result,
+// as healthcheck.io raises its own alert when pings stop. The channels of the
+// host are only told about notable state changes.
+func (d *Dispatcher) Handle(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+	cfg := d.source.GetConfig()
+	d.prune(cfg)
+	event := Event{Host: host, Check: check, Result: result, Status: status}
+	notifyStatus, notable := d.notable(models.CheckID(host.Name, result.Index), models.CheckFingerprint(check), status)
+
+	if check.HealthcheckIOURL != "" {
+		d.send(ctx, models.Channel{
+			Name:    "healthchecks.io",
+			Type:    models.ChannelTypeHealthchecks,
+			Options: map[string]string{"url": check.HealthcheckIOURL},
+		}, event)
+	}
+
+	if len(host.Notify) == 0 || !notable {
+		return
+	}
+
+	event.Status = notifyStatus
+	event.DashboardURL = DashboardURL(cfg)
+	for _, name := range host.Notify {
+		channel, ok := findChannel(cfg.Notifications, name)
+		if !ok {
+			log.Printf("Unknown notification channel %s for host %s", name, host.Name)
+			continue
+		}
+		d.send(ctx, channel, event)
+	}
+}
+
+// Wait sends any notifications that are held back and blocks until all
+// notifications in flight have been sent or given up on
+func (d *Dispatcher) Wait() {
+	for _, notifier := range d.registry.GetAll() {
+		if f, ok := notifier.(Flusher); ok {
+			f.Flush()
+		}
+	}
+	d.sends.Wait()
+}
+
+// prune forgets the checks that were removed from the configuration or
+// replaced by another check, here and in the notifiers that remember checks
+func (d *Dispatcher) prune(cfg *models.Config) {
+	fingerprints := models.CheckFingerprints(cfg)
+
+	d.mu.Lock()
+	for key, last := range d.notified {
+		if fingerprint, ok := fingerprints[key]; !ok || fingerprint != last.fingerprint {
+			delete(d.notified, key)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, notifier := range d.registry.GetAll() {
+		if p, ok := notifier.(Pruner); ok {
+			p.Prune(fingerprints)
+		}
+	}
+}
+
+// send notifies a channel in the background
+func (d *Dispatcher) send(ctx context.Context, channel models.Channel, event Event) {
+	notifier, err := d.registry.Get(channel.Type)
+	if err != nil {
+		log.Printf("Failed to notify %s: %v", channel.Name, err)
+		return
+	}
+
+	d.sends.Add(1)
+	go func() {
+		defer d.sends.Done()
+		if err := notifier.Notify(ctx, channel, event); err != nil {
+			log.Printf("Failed to notify %s for %s/%s: %v", channel.Name, event.Host.Name, event.Check.Type, err)
+		}
+	}()
+}
+
+// notable reports whether a status is worth a notification: a check going
+// DOWN or FLAPPING, or coming back UP after either was notified. The status
+// is compared with the last state notified for the check rather than its
+// previous state, so a spell in maintenance or unreachable during an outage
+// neither hides the recovery nor repeats the alert. Checks coming UP for the
+// first time, entering or leaving maintenance, or becoming unreachable
+// because a parent is down are not notable.
+//
+// For recoveries, the returned status has the last notified state as Previous
+// and when it began as PrevSince, so the recovery reports the whole outage.
+// A state notified for another check with the same key, one that was
+// replaced, is ignored.
+func (d *Dispatcher) notable(key, fingerprint string, status models.Status) (models.Status, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	last, seen := d.notified[key]
+	if seen && last.fingerprint != fingerprint {
+		delete(d.notified, key)
+		seen = false
+	}
+	switch status.State {
+	case models.StateDown, models.StateFlapping:
+		if seen && last.state == status.State {
+			return status, false
+		}
+	case models.StateUp:
+		if !seen || (last.state != models.StateDown && last.state != models.StateFlapping) {
+			return status, false
+		}
+	default:
+		return status, false
+	}
+
+	d.notified[key] = notified{fingerprint: fingerprint, state: status.State, since: status.Since}
+	if status.State == models.StateUp {
+		status.Previous = last.state
+		status.PrevSince = last.since
+	}
+	return status, true
+}
+
+// DashboardURL returns the address of the dashboard linked from
+// notifications: the dashboard_url of the configuration, or the local web
+// server when none is set
+func DashboardURL(cfg *models.Config) string {
+	if cfg.DashboardURL != "" {
+		return strings.TrimSuffix(cfg.DashboardURL, "/")
+	}
+	return fmt.Sprintf("http://localhost:%d", cfg.WebServerPort)
+}
+
+// findChannel returns the channel with the given name
+func findChannel(channels []models.Channel, name string) (models.Channel, bool) {
+	for _, channel := range channels {
+		if channel.Name == name {
+			return channel, true
+		}
+	}
+	return models.Channel{}, false
+}
+
+// Payload is the data about an event that notifications are built from. It
+// is the default webhook body and the data passed to message templates.
+type Payload struct {
+	Host      string           `json:"host"`
+	Address   string           `json:"address"`
+	Tags      []string         `json:"tags,omitempty"`
+	Check     models.CheckType `json:"check"`
+	State     models.State     `json:"state"`
+	Previous  models.State     `json:"previous_state"`
+	Level     models.Level     `json:"level"`
+	Message   string           `json:"message"`
+	Latency   time.Duration    `json:"-"`
+	LatencyMS int64            `json:"latency_ms"`
+	Since     time.Time        `json:"since"`
+	Reason    string           `json:"reason,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	// Outage is how long the check was down or flapping, set when it recovers
+	Outage        time.Duration `json:"-"`
+	OutageSeconds int64         `json:"outage_seconds,omitempty"`
+	DashboardURL  string        `json:"dashboard_url,omitempty"`
+}
+
+// Recovered reports whether the check came back up after being down or flapping
+func (p Payload) Recovered() bool {
+	return p.State == models.StateUp && (p.Previous == models.StateDown || p.Previous == models.StateFlapping)
+}
+
+// NewPayload returns the payload of an event
+func NewPayload(event Event) Payload {
+	timestamp := event.Result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	payload := Payload{
+		Host:      event.Host.Name,
+		Address:   event.Host.Address,
+		Tags:      event.Host.Tags,
+		Check:     event.Check.Type,
+		State:     event.Status.State,
+		Previous:  event.Status.Previous,
+		Level:     event.Result.Level,
+		Message:   event.Result.Message,
+		Latency:   event.Result.Duration,
+		LatencyMS: event.Result.Duration.Milliseconds(),
+		Since:     event.Status.Since,
+		Reason:    event.Status.Reason,
+		Timestamp: timestamp,
+
+		DashboardURL: event.DashboardURL,
+	}
+	if payload.Recovered() && !event.Status.PrevSince.IsZero() {
+		payload.Outage = event.Status.Since.Sub(event.Status.PrevSince)
+		payload.OutageSeconds = int64(payload.Outage.Seconds())
+	}
+	return payload
+}
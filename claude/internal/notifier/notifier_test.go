@@ -0,0 +1,182 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// recordingNotifier records the channels and states it is notified about
+type recordingNotifier struct {
+	channelType models.ChannelType
+	mu          sync.Mutex
+	sent        []string
+}
+
+func (r *recordingNotifier) Type() models.ChannelType { return r.channelType }
+
+func (r *recordingNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, channel.Name+":"+string(event.Status.State))
+	return nil
+}
+
+type staticConfig struct{ cfg *models.Config }
+
+func (s staticConfig) GetConfig() *models.Config { return s.cfg }
+
+func TestNotable(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []models.State // Confirmed states of one check in order, starting from PENDING
+		want   []string       // Notified changes, as previous -> state
+	}{
+		{"first up", []models.State{models.StateUp, models.StateUp}, nil},
+		{"down and recovery", []models.State{models.StateUp, models.StateDown, models.StateDown, models.StateUp}, []string{"UP->DOWN", "DOWN->UP"}},
+		{"down on first run", []models.State{models.StateDown}, []string{"PENDING->DOWN"}},
+		{"flapping", []models.State{models.StateUp, models.StateFlapping, models.StateDown, models.StateUp}, []string{"UP->FLAPPING", "FLAPPING->DOWN", "DOWN->UP"}},
+		{"maintenance while up", []models.State{models.StateUp, models.StateMaintenance, models.StateUp}, nil},
+		{"down after maintenance", []models.State{models.StateMaintenance, models.StateDown}, []string{"MAINTENANCE->DOWN"}},
+		{"recovery after maintenance", []models.State{models.StateDown, models.StateMaintenance, models.StateUp}, []string{"PENDING->DOWN", "DOWN->UP"}},
+		{"recovery after unreachable", []models.State{models.StateDown, models.StateUnreachable, models.StateUp}, []string{"PENDING->DOWN", "DOWN->UP"}},
+		{"no repeat after maintenance", []models.State{models.StateDown, models.StateMaintenance, models.StateDown}, []string{"PENDING->DOWN"}},
+		{"unreachable while up", []models.State{models.StateUp, models.StateUnreachable, models.StateUp}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := NewDispatcher(NewRegistry(), staticConfig{&models.Config{}})
+			previous := models.StatePending
+			var got []string
+			for _, state := range tt.states {
+				status, ok := dispatcher.notable("web-1#0", "http", models.Status{Previous: previous, State: state})
+				if ok {
+					got = append(got, string(status.Previous)+"->"+string(status.State))
+				}
+				previous = state
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Expected notifications %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNotableOutageSpansMaintenance(t *testing.T) {
+	dispatcher := NewDispatcher(NewRegistry(), staticConfig{&models.Config{}})
+	start := time.Now()
+
+	dispatcher.notable("web-1#0", "http", models.Status{Previous: models.StateUp, State: models.StateDown, Since: start})
+	dispatcher.notable("web-1#0", "http", models.Status{Previous: models.StateDown, State: models.StateMaintenance, Since: start.Add(time.Hour), PrevSince: start})
+	status, ok := dispatcher.notable("web-1#0", "http", models.Status{Previous: models.StateMaintenance, State: models.StateUp, Since: start.Add(2 * time.Hour), PrevSince: start.Add(time.Hour)})
+	if !ok {
+		t.Fatal("Expected the recovery to be notified")
+	}
+
+	payload := NewPayload(Event{Status: status})
+	if !payload.Recovered() || payload.Outage != 2*time.Hour {
+		t.Errorf("Expected a recovery after a 2h outage, got recovered=%v outage=%v", payload.Recovered(), payload.Outage)
+	}
+
+	// Other checks of the host are tracked on their own
+	if _, ok := dispatcher.notable("web-1#1", "http", models.Status{Previous: models.StateDown, State: models.StateUp}); ok {
+		t.Error("Expected no recovery for a check that was never notified as down")
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	webhooks := &recordingNotifier{channelType: models.ChannelTypeWebhook}
+	pings := &recordingNotifier{channelType: models.ChannelTypeHealthchecks}
+	registry := NewRegistry()
+	registry.Register(webhooks)
+	registry.Register(pings)
+
+	host := models.Host{Name: "web-1", Notify: []string{"ops", "missing"}}
+	check := models.Check{Type: models.CheckTypeHTTP, HealthcheckIOURL: "https://hc-ping.com/uuid"}
+	host.Checks = []models.Check{check}
+	cfg := &models.Config{Hosts: []models.Host{host}, Notifications: []models.Channel{
+		{Name: "ops", Type: models.ChannelTypeWebhook},
+		{Name: "dev", Type: models.ChannelTypeWebhook},
+	}}
+	dispatcher := NewDispatcher(registry, staticConfig{cfg})
+
+	for _, status := range []models.Status{
+		{Previous: models.StatePending, State: models.StateUp},
+		{Previous: models.StateUp, State: models.StateUp},
+		{Previous: models.StateUp, State: models.StateDown},
+		{Previous: models.StateDown, State: models.StateDown},
+		{Previous: models.StateDown, State: models.StateUp},
+	} {
+		dispatcher.Handle(context.Background(), host, check, models.CheckResult{}, status)
+	}
+	dispatcher.Wait()
+
+	if len(pings.sent) != 5 {
+		t.Errorf("Expected healthcheck.io to be pinged for all 5 results, got %d", len(pings.sent))
+	}
+
+	// Sends are concurrent, so only the set of notifications is checked
+	want := map[string]bool{"ops:DOWN": true, "ops:UP": true}
+	if len(webhooks.sent) != len(want) {
+		t.Fatalf("Expected notifications %v, got %v", want, webhooks.sent)
+	}
+	for _, sent := range webhooks.sent {
+		if !want[sent] {
+			t.Errorf("Unexpected notification %s", sent)
+		}
+	}
+}
+
+func TestDispatcherForgetsReplacedChecks(t *testing.T) {
+	webhooks := &recordingNotifier{channelType: models.ChannelTypeWebhook}
+	registry := NewRegistry()
+	registry.Register(webhooks)
+
+	api := models.Check{Type: models.CheckTypeHTTP, Options: map[string]string{"url": "https://web-1/api"}}
+	home := models.Check{Type: models.CheckTypeHTTP, Options: map[string]string{"url": "https://web-1/"}}
+	host := models.Host{Name: "web-1", Notify: []string{"ops"}, Checks: []models.Check{api, home}}
+	cfg := &models.Config{Hosts: []models.Host{host}, Notifications: []models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook}}}
+	dispatcher := NewDispatcher(registry, staticConfig{cfg})
+
+	down := models.Status{Previous: models.StateUp, State: models.StateDown}
+	up := models.Status{Previous: models.StateDown, State: models.StateUp}
+	dispatcher.Handle(context.Background(), host, api, models.CheckResult{Index: 0}, down)
+
+	// The api check is deleted and the home check moves to its position: its
+	// recovery is not taken for the api check's
+	host.Checks = []models.Check{home}
+	cfg.Hosts[0] = host
+	dispatcher.Handle(context.Background(), host, home, models.CheckResult{Index: 0}, up)
+	dispatcher.Wait()
+	if len(webhooks.sent) != 1 {
+		t.Errorf("Expected only the api check going down to be notified, got %v", webhooks.sent)
+	}
+
+	dispatcher.Handle(context.Background(), host, home, models.CheckResult{Index: 0}, down)
+	cfg.Hosts = nil
+	dispatcher.Handle(context.Background(), models.Host{Name: "db-1"}, home, models.CheckResult{}, models.Status{State: models.StateUp})
+	dispatcher.Wait()
+	if len(dispatcher.notified) != 0 {
+		t.Errorf("Expected the checks of removed hosts to be forgotten, got %v", dispatcher.notified)
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewWebhookNotifier())
+
+	if err := registry.Validate([]models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook, Options: map[string]string{"url": "https://example.com"}}}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := registry.Validate([]models.Channel{{Name: "ops", Type: "carrier-pigeon"}}); err == nil {
+		t.Error("Expected error for unknown channel type")
+	}
+	if err := registry.Validate([]models.Channel{{Name: "ops", Type: models.ChannelTypeWebhook}}); err == nil {
+		t.Error("Expected error for webhook without url")
+	}
+}
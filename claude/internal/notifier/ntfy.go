@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// ntfyPriorities are the names ntfy accepts for its priorities 1 to 5
+var ntfyPriorities = map[string]int{"min": 1, "low": 2, "default": 3, "high": 4, "max": 5, "urgent": 5}
+
+// NtfyNotifier publishes state changes to an ntfy topic, on ntfy.sh or a
+// self-hosted server.
+//
+// Options:
+//   - url: the topic URL, e.g. https://ntfy.sh/my-alerts (required)
+//   - priority: the priority of failing checks, 1 to 5 or min, low, default,
+//     high or max (default: high). Recoveries are sent with the default priority
+//   - tags: comma separated tags, shown as emojis when they name one
+//   - token: an access token, or username and password, for protected topics
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type NtfyNotifier struct {
+	httpClient *http.Client
+}
+
+// NewNtfyNotifier creates a new ntfy notifier
+func NewNtfyNotifier() *NtfyNotifier {
+	return &NtfyNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (n *NtfyNotifier) Type() models.ChannelType {
+	return models.ChannelTypeNtfy
+}
+
+// Validate checks the topic URL, priority and retry options of the channel
+func (n *NtfyNotifier) Validate(channel models.Channel) error {
+	_, err := parseNtfyOptions(channel.Options)
+	return err
+}
+
+// ntfyPayload is a message published as JSON. JSON messages go to the root
+// URL of the server and name their topic.
+type ntfyPayload struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+}
+
+// ntfyOptions are the options of an ntfy channel
+type ntfyOptions struct {
+	server   string // Root URL of the server, JSON messages are posted to it
+	topic    string
+	priority int // Priority of failing checks
+	tags     []string
+	policy   retryPolicy
+}
+
+// Notify publishes the event to the topic
+func (n *NtfyNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	options, err := parseNtfyOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	msg := newPushMessage(event)
+	payload := ntfyPayload{Topic: options.topic, Title: msg.Title, Message: msg.Body, Tags: options.tags, Click: msg.Link}
+	if msg.Failing {
+		payload.Priority = options.priority
+	}
+
+	header := http.Header{}
+	if token := channel.Options["token"]; token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	} else if username := channel.Options["username"]; username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + channel.Options["password"]))
+		header.Set("Authorization", "Basic "+credentials)
+	}
+
+	return sendJSON(ctx, n.httpClient, options.policy, options.server, header, payload)
+}
+
+// parseNtfyOptions reads the options of an ntfy channel, splitting the topic
+// URL into the server URL and the topic
+func parseNtfyOptions(options map[string]string) (ntfyOptions, error) {
+	var parsed ntfyOptions
+	if err := validateURL(options["url"]); err != nil {
+		return parsed, err
+	}
+	u, _ := url.Parse(options["url"])
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	parsed.topic = path[i+1:]
+	if parsed.topic == "" {
+		return parsed, fmt.Errorf("url must include the topic, e.g. https://ntfy.sh/my-alerts")
+	}
+	u.Path = "/" + path[:i+1]
+	u.RawQuery = ""
+	parsed.server = u.String()
+
+	parsed.priority = ntfyPriorities["high"]
+	if raw := options["priority"]; raw != "" {
+		priority, ok := ntfyPriorities[strings.ToLower(raw)]
+		if !ok {
+			var err error
+			priority, err = strconv.Atoi(raw)
+			if err != nil || priority < 1 || priority > 5 {
+				return parsed, fmt.Errorf("invalid priority: %s", raw)
+			}
+		}
+		parsed.priority = priority
+	}
+
+	for _, tag := range strings.Split(options["tags"], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			parsed.tags = append(parsed.tags, tag)
+		}
+	}
+
+	var err error
+	parsed.policy, err = parseRetryPolicy(options)
+	return parsed, err
+}
@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultOpsgenieURL is the Opsgenie API in the US region. Accounts in the EU
+// region use https://api.eu.opsgenie.com.
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// opsgeniePriorities are the default Opsgenie priorities of failing checks
+var opsgeniePriorities = map[string]string{
+	string(models.LevelCritical): "P1",
+	string(models.LevelUnknown):  "P2",
+	string(models.StateFlapping): "P3",
+}
+
+// OpsgenieNotifier creates an Opsgenie alert when a check goes down or starts
+// flapping, and closes it when the check recovers. The alias of the alert is
+// "host/type#index", so repeated failures are deduplicated into the open
+// alert.
+//
+// Options:
+//   - api_key: the key of an Opsgenie API integration (required)
+//   - url: the Opsgenie API (default DefaultOpsgenieURL)
+//   - priority.<CRITICAL|UNKNOWN|FLAPPING>: the alert priority, P1 to P5
+//     (defaults P1, P2 and P3)
+//   - responders: comma separated team names the alert is routed to
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type OpsgenieNotifier struct {
+	httpClient *http.Client
+	incidents  *openIncidents
+}
+
+// NewOpsgenieNotifier creates a new Opsgenie notifier
+func NewOpsgenieNotifier() *OpsgenieNotifier {
+	return &OpsgenieNotifier{httpClient: &http.Client{}, incidents: newOpenIncidents()}
+}
+
+// Type returns the channel type
+func (o *OpsgenieNotifier) Type() models.ChannelType {
+	return models.ChannelTypeOpsgenie
+}
+
+// Prune forgets the incidents of checks that were removed or replaced
+func (o *OpsgenieNotifier) Prune(fingerprints map[string]string) {
+	o.incidents.prune(fingerprints)
+}
+
+// Validate checks the API key, URL, priorities and retry options of the channel
+func (o *OpsgenieNotifier) Validate(channel models.Channel) error {
+	_, err := parseOpsgenieOptions(channel.Options)
+	return err
+}
+
+// opsgenieAlert is the body of a create alert request
+type opsgenieAlert struct {
+	Message     string              `json:"message"`
+	Alias       string              `json:"alias"`
+	Description string              `json:"description,omitempty"`
+	Responders  []opsgenieResponder `json:"responders,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Details     map[string]string   `json:"details"`
+	Entity      string              `json:"entity"`
+	Source      string              `json:"source"`
+	Priority    string              `json:"priority"`
+}
+
+type opsgenieResponder struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// opsgenieClose is the body of a close alert request
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// opsgenieSource is the source of alerts in Opsgenie
+const opsgenieSource = "simple-healthchecker"
+
+// Notify creates or closes the alert of the check
+func (o *OpsgenieNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	options, err := parseOpsgenieOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+	header := http.Header{"Authorization": {"GenieKey " + options.key}}
+	endpoint := strings.TrimSuffix(options.url, "/") + "/v2/alerts"
+
+	payload := NewPayload(event)
+	alias := incidentKey(event)
+	switch o.incidents.action(channel, event) {
+	case incidentTrigger:
+		alert := opsgenieAlert{
+			// Opsgenie limits the message to 130 characters and the description to 15000
+			Message:     truncate(incidentSummary(payload), 130),
+			Alias:       alias,
+			Description: truncate(payload.Message, 15000),
+			Tags:        append([]string{string(payload.Check)}, payload.Tags...),
+			Details:     incidentDetails(payload),
+			Entity:      payload.Host,
+			Source:      opsgenieSource,
+			Priority:    options.severities[incidentSeverity(payload)],
+		}
+		for _, team := range strings.Split(channel.Options["responders"], ",") {
+			if team = strings.TrimSpace(team); team != "" {
+				alert.Responders = append(alert.Responders, opsgenieResponder{Name: team, Type: "team"})
+			}
+		}
+		return sendJSON(ctx, o.httpClient, options.policy, endpoint, header, alert)
+
+	case incidentResolve:
+		note := fmt.Sprintf("%s %s check recovered", payload.Host, payload.Check)
+		if payload.Outage > 0 {
+			note += " after " + formatOutage(payload.Outage)
+		}
+		closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", endpoint, url.PathEscape(alias))
+		return sendJSON(ctx, o.httpClient, options.policy, closeURL, header, opsgenieClose{Source: opsgenieSource, Note: note})
+	}
+	return nil
+}
+
+// parseOpsgenieOptions reads the options of an Opsgenie channel
+func parseOpsgenieOptions(options map[string]string) (incidentOptions, error) {
+	return parseIncidentOptions(options, "api_key", DefaultOpsgenieURL, "priority", opsgeniePriorities,
+		[]string{"P1", "P2", "P3", "P4", "P5"})
+}
@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultPagerDutyURL is the PagerDuty Events API v2 endpoint
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutySeverities are the default PagerDuty severities of failing checks
+var pagerDutySeverities = map[string]string{
+	string(models.LevelCritical): "critical",
+	string(models.LevelUnknown):  "error",
+	string(models.StateFlapping): "warning",
+}
+
+// PagerDutyNotifier opens a PagerDuty incident through the Events API v2 when
+// a check goes down or starts flapping, and resolves it when the check
+// recovers. The dedup key of a check is "host/type#index", so repeated
+// failures update the same incident.
+//
+// Options:
+//   - routing_key: the integration key of the PagerDuty service (required)
+//   - url: the Events API endpoint (default DefaultPagerDutyURL)
+//   - severity.<CRITICAL|UNKNOWN|FLAPPING>: the PagerDuty severity, one of
+//     critical, error, warning or info (defaults critical, error and warning)
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type PagerDutyNotifier struct {
+	httpClient *http.Client
+	incidents  *openIncidents
+}
+
+// NewPagerDutyNotifier creates a new PagerDuty notifier
+func NewPagerDutyNotifier() *PagerDutyNotifier {
+	return &PagerDutyNotifier{httpClient: &http.Client{}, incidents: newOpenIncidents()}
+}
+
+// Type returns the channel type
+func (p *PagerDutyNotifier) Type() models.ChannelType {
+	return models.ChannelTypePagerDuty
+}
+
+// Prune forgets the incidents of checks that were removed or replaced
+func (p *PagerDutyNotifier) Prune(fingerprints map[string]string) {
+	p.incidents.prune(fingerprints)
+}
+
+// Validate checks the routing key, URL, severities and retry options of the channel
+func (p *PagerDutyNotifier) Validate(channel models.Channel) error {
+	_, err := parsePagerDutyOptions(channel.Options)
+	return err
+}
+
+// pagerDutyEvent is an Events API v2 event
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger or resolve
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // Only for trigger
+	Client      string            `json:"client,omitempty"`
+	ClientURL   string            `json:"client_url,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class"`
+	CustomDetails map[string]string `json:"custom_details"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Notify triggers or resolves the incident of the check
+func (p *PagerDutyNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	options, err := parsePagerDutyOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	payload := NewPayload(event)
+	message := pagerDutyEvent{
+		RoutingKey: options.key,
+		DedupKey:   incidentKey(event),
+	}
+	switch p.incidents.action(channel, event) {
+	case incidentTrigger:
+		source := payload.Address
+		if source == "" {
+			source = payload.Host
+		}
+		message.EventAction = "trigger"
+		message.Payload = &pagerDutyPayload{
+			// The summary is limited to 1024 characters
+			Summary:       truncate(incidentSummary(payload), 1024),
+			Source:        source,
+			Severity:      options.severities[incidentSeverity(payload)],
+			Timestamp:     payload.Timestamp.Format(time.RFC3339),
+			Component:     payload.Host,
+			Group:         firstTag(payload.Tags),
+			Class:         string(payload.Check),
+			CustomDetails: incidentDetails(payload),
+		}
+		if payload.DashboardURL != "" {
+			message.Client = "Simple Healthchecker"
+			message.ClientURL = payload.DashboardURL
+			message.Links = []pagerDutyLink{{Href: payload.DashboardURL, Text: "Open dashboard"}}
+		}
+	case incidentResolve:
+		message.EventAction = "resolve"
+	default:
+		return nil
+	}
+
+	return sendJSON(ctx, p.httpClient, options.policy, options.url, nil, message)
+}
+
+// parsePagerDutyOptions reads the options of a PagerDuty channel
+func parsePagerDutyOptions(options map[string]string) (incidentOptions, error) {
+	return parseIncidentOptions(options, "routing_key", DefaultPagerDutyURL, "severity", pagerDutySeverities,
+		[]string{"critical", "error", "warning", "info"})
+}
+
+// firstTag returns the first tag of a host, used to group its incidents
+func firstTag(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pushMessage is the content of a phone push notification. Push services
+// show a title and a short plain text body, so the facts of the chat message
+// are written as lines under the check message.
+type pushMessage struct {
+	Title   string
+	Body    string
+	Link    string // The dashboard, opened when the notification is tapped
+	Failing bool   // The check is down or flapping, sent with the channel priority
+	Tag     string // Identifies the check, "host/type#index"
+}
+
+// newPushMessage builds the push notification for an event
+func newPushMessage(event Event) pushMessage {
+	chat := newChatMessage(event)
+	payload := NewPayload(event)
+
+	lines := make([]string, 0, len(chat.Facts)+1)
+	if chat.Message != "" {
+		lines = append(lines, chat.Message)
+	}
+	for _, f := range chat.Facts {
+		// The title already names the host and check
+		if f.Name == "Host" || f.Name == "Check" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", f.Name, f.Value))
+	}
+
+	return pushMessage{
+		Title:   chat.Title,
+		Body:    strings.Join(lines, "\n"),
+		Link:    chat.Link,
+		Failing: incidentActionFor(payload) == incidentTrigger,
+		Tag:     incidentKey(event),
+	}
+}
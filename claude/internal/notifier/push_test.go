@@ -0,0 +1,190 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestNewPushMessage(t *testing.T) {
+	down := testEvent()
+	down.DashboardURL = "https://status.example.com"
+	msg := newPushMessage(down)
+	if msg.Title != "🔴 web-1 http check is DOWN" || !msg.Failing || msg.Tag != "web-1/http#0" {
+		t.Errorf("Unexpected push message %+v", msg)
+	}
+	if want := "status \"503\"\nState: UP → DOWN\nLevel: CRITICAL\nLatency: 1.5s"; msg.Body != want {
+		t.Errorf("Expected body %q, got %q", want, msg.Body)
+	}
+
+	recovery := newPushMessage(recoveryEvent())
+	if recovery.Failing || !strings.HasSuffix(recovery.Body, "Outage: 5m30s") {
+		t.Errorf("Unexpected recovery message %+v", recovery)
+	}
+}
+
+func TestNtfyNotifier(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusOK)
+	notifier := NewNtfyNotifier()
+	channel := models.Channel{Name: "ntfy", Type: models.ChannelTypeNtfy, Options: map[string]string{
+		"url":   server.URL + "/homelab-alerts",
+		"tags":  "warning, homelab",
+		"token": "tk_secret",
+	}}
+
+	for _, event := range []Event{testEvent(), recoveryEvent()} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	var messages []ntfyPayload
+	for _, r := range got {
+		if r.path != "/" {
+			t.Errorf("Expected JSON to be published to the server root, got %s", r.path)
+		}
+		if auth := r.header.Get("Authorization"); auth != "Bearer tk_secret" {
+			t.Errorf("Expected the access token, got %q", auth)
+		}
+		var msg ntfyPayload
+		if err := json.Unmarshal(r.body, &msg); err != nil {
+			t.Fatalf("Expected a JSON body, got %s", r.body)
+		}
+		messages = append(messages, msg)
+	}
+
+	if down := messages[0]; down.Topic != "homelab-alerts" || down.Priority != 4 || len(down.Tags) != 2 || down.Tags[1] != "homelab" {
+		t.Errorf("Unexpected down message %+v", down)
+	}
+	if up := messages[1]; up.Priority != 0 || up.Click != "https://status.example.com" {
+		t.Errorf("Expected the recovery with the default priority and a dashboard link, got %+v", up)
+	}
+}
+
+func TestGotifyNotifier(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusOK)
+	notifier := NewGotifyNotifier()
+	channel := models.Channel{Name: "gotify", Type: models.ChannelTypeGotify, Options: map[string]string{
+		"url":   server.URL + "/",
+		"token": "AppT0ken",
+	}}
+
+	for _, event := range []Event{testEvent(), recoveryEvent()} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.path != "/message" || r.header.Get("X-Gotify-Key") != "AppT0ken" {
+			t.Errorf("Expected a message with the app token, got %s %v", r.path, r.header)
+		}
+	}
+	if !strings.Contains(string(got[0].body), `"priority":8`) {
+		t.Errorf("Expected the down message with priority 8, got %s", got[0].body)
+	}
+	if strings.Contains(string(got[1].body), `"priority"`) {
+		t.Errorf("Expected the recovery with the default priority, got %s", got[1].body)
+	}
+	if !strings.Contains(string(got[1].body), `"client::notification":{"click":{"url":"https://status.example.com"}}`) {
+		t.Errorf("Expected a click URL, got %s", got[1].body)
+	}
+}
+
+func TestPushoverNotifierEmergency(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusOK)
+	notifier := NewPushoverNotifier()
+	channel := models.Channel{Name: "pushover", Type: models.ChannelTypePushover, Options: map[string]string{
+		"url":             server.URL,
+		"app_token":       "app",
+		"user_key":        "user",
+		"priority":        "2",
+		"emergency_retry": "2m",
+	}}
+
+	// The check goes down, then unreachable behind its parent, then up
+	unreachable := testEvent()
+	unreachable.Status = models.Status{State: models.StateUnreachable, Previous: models.StateDown}
+	up := recoveryEvent()
+	up.Status.Previous = models.StateUnreachable
+
+	for _, event := range []Event{testEvent(), unreachable, up} {
+		if err := notifier.Notify(context.Background(), channel, event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 4 {
+		t.Fatalf("Expected the down message, the unreachable message, the cancel and the up message, got %d requests", len(got))
+	}
+	paths := []string{"/messages.json", "/messages.json", "/receipts/cancel_by_tag/web-1%2Fhttp%230.json", "/messages.json"}
+	forms := make([]url.Values, len(got))
+	for i, r := range got {
+		if r.path != paths[i] {
+			t.Errorf("Expected request %d to %s, got %s", i, paths[i], r.path)
+		}
+		form, err := url.ParseQuery(string(r.body))
+		if err != nil {
+			t.Fatalf("Expected a form, got %s", r.body)
+		}
+		if form.Get("token") != "app" {
+			t.Errorf("Expected the app token in request %d, got %q", i, form.Get("token"))
+		}
+		forms[i] = form
+	}
+
+	down := forms[0]
+	if down.Get("user") != "user" || down.Get("priority") != "2" || down.Get("retry") != "120" || down.Get("expire") != "3600" || down.Get("tags") != "web-1/http#0" {
+		t.Errorf("Unexpected emergency message %v", down)
+	}
+	if forms[1].Get("priority") != "" || forms[1].Get("tags") != "" {
+		t.Errorf("Expected the unreachable message without emergency priority, got %v", forms[1])
+	}
+	if upForm := forms[3]; upForm.Get("priority") != "" || upForm.Get("url") != "https://status.example.com" {
+		t.Errorf("Unexpected up message %v", upForm)
+	}
+}
+
+func TestPushNotifiersValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier Notifier
+		options  map[string]string
+		wantErr  bool
+	}{
+		{"ntfy", NewNtfyNotifier(), map[string]string{"url": "https://ntfy.sh/alerts", "priority": "urgent"}, false},
+		{"ntfy without topic", NewNtfyNotifier(), map[string]string{"url": "https://ntfy.sh/"}, true},
+		{"ntfy bad priority", NewNtfyNotifier(), map[string]string{"url": "https://ntfy.sh/alerts", "priority": "6"}, true},
+		{"gotify", NewGotifyNotifier(), map[string]string{"url": "https://gotify.example.com", "token": "t", "priority": "10"}, false},
+		{"gotify without token", NewGotifyNotifier(), map[string]string{"url": "https://gotify.example.com"}, true},
+		{"gotify bad priority", NewGotifyNotifier(), map[string]string{"url": "https://gotify.example.com", "token": "t", "priority": "high"}, true},
+		{"pushover", NewPushoverNotifier(), map[string]string{"app_token": "a", "user_key": "u", "priority": "-1"}, false},
+		{"pushover without user", NewPushoverNotifier(), map[string]string{"app_token": "a"}, true},
+		{"pushover bad priority", NewPushoverNotifier(), map[string]string{"app_token": "a", "user_key": "u", "priority": "3"}, true},
+		{"pushover short retry", NewPushoverNotifier(), map[string]string{"app_token": "a", "user_key": "u", "priority": "2", "emergency_retry": "10s"}, true},
+		{"pushover long expire", NewPushoverNotifier(), map[string]string{"app_token": "a", "user_key": "u", "priority": "2", "emergency_expire": "4h"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.notifier.(Validator).Validate(models.Channel{Name: tt.name, Type: tt.notifier.Type(), Options: tt.options})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
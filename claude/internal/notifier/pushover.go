@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// Pushover defaults
+const (
+	DefaultPushoverURL = "https://api.pushover.net/1"
+	// DefaultPushoverPriority is high priority, which bypasses quiet hours
+	DefaultPushoverPriority = 1
+	// Emergency notifications repeat every DefaultPushoverRetry until they are
+	// acknowledged or DefaultPushoverExpire has passed
+	DefaultPushoverRetry  = time.Minute
+	DefaultPushoverExpire = time.Hour
+)
+
+// pushoverEmergency is the priority that repeats until acknowledged
+const pushoverEmergency = 2
+
+// PushoverNotifier sends state changes to Pushover.
+//
+// Options:
+//   - app_token: the API token of the Pushover application (required)
+//   - user_key: the user or group key to notify (required)
+//   - priority: the priority of failing checks, -2 to 2 (default: 1).
+//     Recoveries are sent with normal priority
+//   - emergency_retry, emergency_expire: with priority 2, how often the
+//     notification repeats until acknowledged (default: 1m, at least 30s) and
+//     for how long (default: 1h, at most 3h). The repeats are cancelled when
+//     the check is next up
+//   - url: the Pushover API (default DefaultPushoverURL)
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type PushoverNotifier struct {
+	httpClient  *http.Client
+	emergencies *openIncidents // Checks with repeating emergency notifications
+}
+
+// NewPushoverNotifier creates a new Pushover notifier
+func NewPushoverNotifier() *PushoverNotifier {
+	return &PushoverNotifier{httpClient: &http.Client{}, emergencies: newOpenIncidents()}
+}
+
+// Type returns the channel type
+func (p *PushoverNotifier) Type() models.ChannelType {
+	return models.ChannelTypePushover
+}
+
+// Prune forgets the emergencies of checks that were removed or replaced
+func (p *PushoverNotifier) Prune(fingerprints map[string]string) {
+	p.emergencies.prune(fingerprints)
+}
+
+// Validate checks the keys, priority and retry options of the channel
+func (p *PushoverNotifier) Validate(channel models.Channel) error {
+	_, err := parsePushoverOptions(channel.Options)
+	return err
+}
+
+// pushoverOptions are the options of a Pushover channel
+type pushoverOptions struct {
+	url      string
+	appToken string
+	userKey  string
+	priority int // Priority of failing checks
+	retry    time.Duration
+	expire   time.Duration
+	policy   retryPolicy
+}
+
+// Notify sends the event to Pushover. Emergency notifications are tagged
+// with the check, so they can be cancelled when it is up again.
+func (p *PushoverNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	options, err := parsePushoverOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	msg := newPushMessage(event)
+	form := url.Values{
+		"token": {options.appToken},
+		"user":  {options.userKey},
+		// Titles are limited to 250 characters and messages to 1024
+		"title":   {truncate(msg.Title, 250)},
+		"message": {truncate(msg.Body, 1024)},
+	}
+	if ts := NewPayload(event).Timestamp; !ts.IsZero() {
+		form.Set("timestamp", strconv.FormatInt(ts.Unix(), 10))
+	}
+	if msg.Link != "" {
+		form.Set("url", msg.Link)
+		form.Set("url_title", "Open dashboard")
+	}
+
+	if msg.Failing {
+		form.Set("priority", strconv.Itoa(options.priority))
+	}
+	var cancelErr error
+	if options.priority == pushoverEmergency {
+		switch p.emergencies.action(channel, event) {
+		case incidentTrigger:
+			form.Set("retry", strconv.Itoa(int(options.retry.Seconds())))
+			form.Set("expire", strconv.Itoa(int(options.expire.Seconds())))
+			form.Set("tags", msg.Tag)
+		case incidentResolve:
+			cancel := url.Values{"token": {options.appToken}}
+			cancelURL := fmt.Sprintf("%s/receipts/cancel_by_tag/%s.json", options.url, url.PathEscape(msg.Tag))
+			if err := p.postForm(ctx, options.policy, cancelURL, cancel); err != nil {
+				cancelErr = fmt.Errorf("failed to cancel emergency notification: %w", err)
+			}
+		}
+	}
+
+	return errors.Join(cancelErr, p.postForm(ctx, options.policy, options.url+"/messages.json", form))
+}
+
+// postForm posts a form to the Pushover API
+func (p *PushoverNotifier) postForm(ctx context.Context, policy retryPolicy, target string, form url.Values) error {
+	body := form.Encode()
+	return post(ctx, p.httpClient, policy, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+}
+
+// parsePushoverOptions reads the options of a Pushover channel
+func parsePushoverOptions(options map[string]string) (pushoverOptions, error) {
+	parsed := pushoverOptions{
+		url:      strings.TrimSuffix(options["url"], "/"),
+		appToken: options["app_token"],
+		userKey:  options["user_key"],
+		priority: DefaultPushoverPriority,
+		retry:    DefaultPushoverRetry,
+		expire:   DefaultPushoverExpire,
+	}
+	if parsed.appToken == "" {
+		return parsed, fmt.Errorf("app_token is required")
+	}
+	if parsed.userKey == "" {
+		return parsed, fmt.Errorf("user_key is required")
+	}
+	if parsed.url == "" {
+		parsed.url = DefaultPushoverURL
+	}
+	if err := validateURL(parsed.url); err != nil {
+		return parsed, err
+	}
+
+	if raw := options["priority"]; raw != "" {
+		priority, err := strconv.Atoi(raw)
+		if err != nil || priority < -2 || priority > pushoverEmergency {
+			return parsed, fmt.Errorf("invalid priority: %s", raw)
+		}
+		parsed.priority = priority
+	}
+	if raw := options["emergency_retry"]; raw != "" {
+		retry, err := time.ParseDuration(raw)
+		if err != nil || retry < 30*time.Second {
+			return parsed, fmt.Errorf("invalid emergency_retry: %s (at least 30s)", raw)
+		}
+		parsed.retry = retry
+	}
+	if raw := options["emergency_expire"]; raw != "" {
+		expire, err := time.ParseDuration(raw)
+		if err != nil || expire <= 0 || expire > 3*time.Hour {
+			return parsed, fmt.Errorf("invalid emergency_expire: %s (at most 3h)", raw)
+		}
+		parsed.expire = expire
+	}
+
+	var err error
+	parsed.policy, err = parseRetryPolicy(options)
+	return parsed, err
+}
@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// SlackNotifier posts state changes to a Slack incoming webhook as Block Kit
+// messages.
+//
+// Options:
+//   - url: the incoming webhook URL (required)
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type SlackNotifier struct {
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a new Slack notifier
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (s *SlackNotifier) Type() models.ChannelType {
+	return models.ChannelTypeSlack
+}
+
+// Validate checks the webhook URL and retry options of the channel
+func (s *SlackNotifier) Validate(channel models.Channel) error {
+	return validateChat(channel)
+}
+
+// Notify posts the event to Slack
+func (s *SlackNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	return postJSON(ctx, s.httpClient, channel, slackMessage(newChatMessage(event)))
+}
+
+type slackPayload struct {
+	Text   string       `json:"text"` // Shown in notifications and clients without blocks
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackMessage renders a chat message as Slack blocks: a header, the facts
+// as fields, the check message and a link to the dashboard
+func slackMessage(msg chatMessage) slackPayload {
+	fields := make([]slackText, 0, len(msg.Facts))
+	for _, f := range msg.Facts {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", f.Name, slackEscape(f.Value))})
+	}
+
+	blocks := []slackBlock{
+		// Header text is limited to 150 characters
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(msg.Title, 150)}},
+		{Type: "section", Fields: fields},
+	}
+	if msg.Message != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncate("*Message*\n"+slackEscape(msg.Message), 3000)}})
+	}
+	if msg.Link != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: fmt.Sprintf("<%s|Open dashboard>", msg.Link)}}})
+	}
+
+	return slackPayload{Text: msg.Title, Blocks: blocks}
+}
+
+// slackEscape escapes the characters Slack treats as control characters in mrkdwn
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
@@ -0,0 +1,123 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// TeamsNotifier posts state changes to a Microsoft Teams incoming webhook or
+// workflow as Adaptive Cards.
+//
+// Options:
+//   - url: the webhook URL (required)
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type TeamsNotifier struct {
+	httpClient *http.Client
+}
+
+// NewTeamsNotifier creates a new Microsoft Teams notifier
+func NewTeamsNotifier() *TeamsNotifier {
+	return &TeamsNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (t *TeamsNotifier) Type() models.ChannelType {
+	return models.ChannelTypeTeams
+}
+
+// Validate checks the webhook URL and retry options of the channel
+func (t *TeamsNotifier) Validate(channel models.Channel) error {
+	return validateChat(channel)
+}
+
+// Notify posts the event to Teams
+func (t *TeamsNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	return postJSON(ctx, t.httpClient, channel, teamsMessage(newChatMessage(event)))
+}
+
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+	Actions []teamsAction  `json:"actions,omitempty"`
+}
+
+type teamsElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Color  string      `json:"color,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// teamsMessage renders a chat message as an Adaptive Card with the facts in
+// a fact set and a button that opens the dashboard
+func teamsMessage(msg chatMessage) teamsPayload {
+	facts := make([]teamsFact, 0, len(msg.Facts))
+	for _, f := range msg.Facts {
+		facts = append(facts, teamsFact{Title: f.Name, Value: f.Value})
+	}
+
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []teamsElement{
+			{Type: "TextBlock", Text: msg.Title, Weight: "Bolder", Size: "Medium", Color: teamsColor(msg.Color), Wrap: true},
+			{Type: "FactSet", Facts: facts},
+		},
+	}
+	if msg.Message != "" {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: msg.Message, Wrap: true})
+	}
+	if msg.Link != "" {
+		card.Actions = []teamsAction{{Type: "Action.OpenUrl", Title: "Open dashboard", URL: msg.Link}}
+	}
+
+	return teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
+
+// teamsColor maps a state color to the closest Adaptive Card text color
+func teamsColor(color int) string {
+	switch color {
+	case colorDown:
+		return "Attention"
+	case colorFlapping:
+		return "Warning"
+	case colorUp:
+		return "Good"
+	}
+	return "Default"
+}
@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultSignatureHeader is the header the HMAC signature of a webhook body
+// is sent in when a secret is set
+const DefaultSignatureHeader = "X-Healthchecker-Signature"
+
+// templateFuncs are available in webhook body templates
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, so that strings are quoted and escaped
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// WebhookNotifier posts a JSON payload to a URL.
+//
+// Options:
+//   - url: the URL to send to (required)
+//   - method: the HTTP method (default POST)
+//   - body: a Go template for the body, executed with a Payload (default the payload as JSON)
+//   - header.<name>: a header to send, e.g. header.Authorization
+//   - secret: signs the body with HMAC-SHA256, sent hex encoded as sha256=<signature>
+//   - signature_header: the header for the signature (default X-Healthchecker-Signature)
+//   - retries, retry_delay, timeout: how failed deliveries are retried
+type WebhookNotifier struct {
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{httpClient: &http.Client{}}
+}
+
+// Type returns the channel type
+func (w *WebhookNotifier) Type() models.ChannelType {
+	return models.ChannelTypeWebhook
+}
+
+// Validate checks the URL, body template and retry options of the channel
+func (w *WebhookNotifier) Validate(channel models.Channel) error {
+	_, _, err := parseWebhookOptions(channel.Options)
+	return err
+}
+
+// Notify sends the event to the webhook
+func (w *WebhookNotifier) Notify(ctx context.Context, channel models.Channel, event Event) error {
+	body, policy, err := parseWebhookOptions(channel.Options)
+	if err != nil {
+		return err
+	}
+
+	payload, err := renderBody(body, NewPayload(event))
+	if err != nil {
+		return err
+	}
+
+	method := strings.ToUpper(channel.Options["method"])
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	signatureHeader := channel.Options["signature_header"]
+	if signatureHeader == "" {
+		signatureHeader = DefaultSignatureHeader
+	}
+
+	return post(ctx, w.httpClient, policy, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, channel.Options["url"], bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range channel.Options {
+			if name, ok := strings.CutPrefix(key, "header."); ok {
+				req.Header.Set(name, value)
+			}
+		}
+		if secret := channel.Options["secret"]; secret != "" {
+			req.Header.Set(signatureHeader, Sign(secret, payload))
+		}
+		return req, nil
+	})
+}
+
+// Sign returns the signature of a webhook body for the given secret, as sent
+// in the signature header. Receivers compute it over the raw body to verify
+// that a request came from the healthchecker.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseWebhookOptions validates the options of a webhook channel and returns
+// its body template, nil for the default body, and retry policy
+func parseWebhookOptions(options map[string]string) (*template.Template, retryPolicy, error) {
+	if err := validateURL(options["url"]); err != nil {
+		return nil, retryPolicy{}, err
+	}
+
+	var body *template.Template
+	if text := options["body"]; text != "" {
+		var err error
+		body, err = template.New("body").Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, retryPolicy{}, fmt.Errorf("invalid body template: %w", err)
+		}
+	}
+
+	policy, err := parseRetryPolicy(options)
+	if err != nil {
+		return nil, retryPolicy{}, err
+	}
+
+	return body, policy, nil
+}
+
+// renderBody executes the body template with the payload, or encodes the
+// payload as JSON when there is no template
+func renderBody(body *template.Template, payload Payload) ([]byte, error) {
+	if body == nil {
+		return json.Marshal(payload)
+	}
+
+	var buf bytes.Buffer
+	if err := body.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// received is a request captured by a webhook test server
+type received struct {
+	path   string // Path and query
+	header http.Header
+	body   []byte
+}
+
+// newWebhookTestServer returns a server that answers with the given status
+// codes in turn, the last one repeating, and records every request
+func newWebhookTestServer(t *testing.T, statuses ...int) (*httptest.Server, func() []received) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, received{path: r.URL.RequestURI(), header: r.Header.Clone(), body: body})
+		status := statuses[min(len(requests), len(statuses))-1]
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), requests...)
+	}
+}
+
+func testEvent() Event {
+	return Event{
+		Host:   models.Host{Name: "web-1", Address: "10.0.0.1", Tags: []string{"prod"}},
+		Check:  models.Check{Type: models.CheckTypeHTTP},
+		Result: models.CheckResult{Level: models.LevelCritical, Message: `status "503"`, Duration: 1500 * time.Millisecond, Timestamp: time.Unix(1700000000, 0).UTC()},
+		Status: models.Status{State: models.StateDown, Previous: models.StateUp},
+	}
+}
+
+func TestWebhookNotifierDefaultBody(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusOK)
+
+	channel := models.Channel{Name: "hook", Type: models.ChannelTypeWebhook, Options: map[string]string{
+		"url":                  server.URL,
+		"header.Authorization": "Bearer token",
+		"secret":               "s3cret",
+	}}
+	if err := NewWebhookNotifier().Notify(context.Background(), channel, testEvent()); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(got))
+	}
+	req := got[0]
+
+	var payload map[string]any
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("Expected a JSON body, got %s: %v", req.body, err)
+	}
+	for key, want := range map[string]any{
+		"host":           "web-1",
+		"check":          "http",
+		"state":          "DOWN",
+		"previous_state": "UP",
+		"level":          "CRITICAL",
+		"message":        `status "503"`,
+		"latency_ms":     float64(1500),
+	} {
+		if payload[key] != want {
+			t.Errorf("Expected %s %v, got %v", key, want, payload[key])
+		}
+	}
+
+	if got := req.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Expected Authorization header 'Bearer token', got %q", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", got)
+	}
+	if got, want := req.header.Get(DefaultSignatureHeader), Sign("s3cret", req.body); got != want {
+		t.Errorf("Expected signature %q, got %q", want, got)
+	}
+}
+
+func TestWebhookNotifierTemplate(t *testing.T) {
+	server, requests := newWebhookTestServer(t, http.StatusNoContent)
+
+	channel := models.Channel{Name: "hook", Type: models.ChannelTypeWebhook, Options: map[string]string{
+		"url":              server.URL,
+		"body":             `{"text": {{ printf "%s/%s is %s: %s" .Host .Check .State .Message | json }}}`,
+		"secret":           "s3cret",
+		"signature_header": "X-Signature",
+	}}
+	if err := NewWebhookNotifier().Notify(context.Background(), channel, testEvent()); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	req := requests()[0]
+	want := `{"text": "web-1/http is DOWN: status \"503\""}`
+	if string(req.body) != want {
+		t.Errorf("Expected body %s, got %s", want, req.body)
+	}
+	if got := req.header.Get("X-Signature"); got != Sign("s3cret", req.body) {
+		t.Errorf("Expected signature in X-Signature, got %q", got)
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		retries      string
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "succeeds first time",
+			statuses:     []int{http.StatusOK},
+			retries:      "3",
+			wantRequests: 1,
+		},
+		{
+			name:         "retries server errors",
+			statuses:     []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			retries:      "3",
+			wantRequests: 3,
+		},
+		{
+			name:         "retries rate limiting",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retries:      "3",
+			wantRequests: 2,
+		},
+		{
+			name:         "gives up after retries",
+			statuses:     []int{http.StatusServiceUnavailable},
+			retries:      "2",
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusBadRequest},
+			retries:      "3",
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "no retries",
+			statuses:     []int{http.StatusInternalServerError},
+			retries:      "0",
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newWebhookTestServer(t, tt.statuses...)
+
+			channel := models.Channel{Name: "hook", Type: models.ChannelTypeWebhook, Options: map[string]string{
+				"url":         server.URL,
+				"retries":     tt.retries,
+				"retry_delay": "1ms",
+			}}
+			err := NewWebhookNotifier().Notify(context.Background(), channel, testEvent())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := len(requests()); got != tt.wantRequests {
+				t.Errorf("Expected %d requests, got %d", tt.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			options: map[string]string{"url": "https://example.com/hook", "body": `{"host": {{ json .Host }}}`, "retries": "5", "retry_delay": "2s"},
+		},
+		{
+			name:    "missing url",
+			options: map[string]string{},
+			wantErr: "url is required",
+		},
+		{
+			name:    "not http",
+			options: map[string]string{"url": "ftp://example.com"},
+			wantErr: "invalid url",
+		},
+		{
+			name:    "bad template",
+			options: map[string]string{"url": "https://example.com/hook", "body": `{{ .Host`},
+			wantErr: "invalid body template",
+		},
+		{
+			name:    "bad retries",
+			options: map[string]string{"url": "https://example.com/hook", "retries": "-1"},
+			wantErr: "invalid retries",
+		},
+		{
+			name:    "bad retry delay",
+			options: map[string]string{"url": "https://example.com/hook", "retry_delay": "soon"},
+			wantErr: "invalid retry_delay",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewWebhookNotifier().Validate(models.Channel{Name: "hook", Type: models.ChannelTypeWebhook, Options: tt.options})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package schedule parses when checks run: at a fixed interval or at the
+// times of a cron expression. It is shared by the configuration validation
+// and the scheduler.
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+	"github.com/robfig/cron/v3"
+)
+
+// MinInterval is the shortest interval a check may run at
+const MinInterval = time.Second
+
+// cronParser accepts standard five-field expressions, an optional leading
+// seconds field and descriptors such as @hourly or @every 10m
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Schedule determines when a check is next due to run
+type Schedule interface {
+	// Next returns the first run time after t
+	Next(t time.Time) time.Time
+}
+
+// Interval runs a check at a fixed interval
+type Interval time.Duration
+
+// Next returns t plus the interval
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// Parse returns the schedule of a check. Checks with neither an interval nor
+// a cron schedule run at the given default interval.
+func Parse(check models.Check, defaultInterval time.Duration) (Schedule, error) {
+	if check.Schedule != "" {
+		if check.Interval != 0 {
+			return nil, fmt.Errorf("interval and schedule cannot both be set")
+		}
+		schedule, err := cronParser.Parse(check.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", check.Schedule, err)
+		}
+		// Next returns the zero time for expressions such as 0 0 30 2 * that
+		// match no date
+		if schedule.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("invalid schedule %q: it never runs", check.Schedule)
+		}
+		return schedule, nil
+	}
+
+	interval := time.Duration(check.Interval)
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	if interval < MinInterval {
+		return nil, fmt.Errorf("interval %v is shorter than %v", interval, MinInterval)
+	}
+	return Interval(interval), nil
+}
+
+// IsInterval reports whether a schedule runs at a fixed interval rather than
+// at cron times
+func IsInterval(s Schedule) bool {
+	_, ok := s.(Interval)
+	return ok
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestParse(t *testing.T) {
+	from := time.Date(2025, 3, 2, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		check        models.Check
+		wantNext     time.Time
+		wantInterval bool
+		wantErr      bool
+	}{
+		{
+			name:         "default interval",
+			check:        models.Check{},
+			wantNext:     from.Add(time.Minute),
+			wantInterval: true,
+		},
+		{
+			name:         "own interval",
+			check:        models.Check{Interval: models.Duration(10 * time.Second)},
+			wantNext:     from.Add(10 * time.Second),
+			wantInterval: true,
+		},
+		{
+			name:     "cron expression",
+			check:    models.Check{Schedule: "0 * * * *"},
+			wantNext: time.Date(2025, 3, 2, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "cron with seconds",
+			check:    models.Check{Schedule: "*/15 * * * * *"},
+			wantNext: time.Date(2025, 3, 2, 10, 20, 45, 0, time.UTC),
+		},
+		{
+			name:     "descriptor",
+			check:    models.Check{Schedule: "@daily"},
+			wantNext: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "interval and schedule",
+			check:   models.Check{Interval: models.Duration(time.Minute), Schedule: "@hourly"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cron",
+			check:   models.Check{Schedule: "every day"},
+			wantErr: true,
+		},
+		{
+			name:    "cron that never runs",
+			check:   models.Check{Schedule: "0 0 30 2 *"},
+			wantErr: true,
+		},
+		{
+			name:    "interval below minimum",
+			check:   models.Check{Interval: models.Duration(500 * time.Millisecond)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.check, time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if next := got.Next(from); !next.Equal(tt.wantNext) {
+				t.Errorf("Next() = %v, want %v", next, tt.wantNext)
+			}
+			if IsInterval(got) != tt.wantInterval {
+				t.Errorf("IsInterval() = %v, want %v", IsInterval(got), tt.wantInterval)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultBackoffAfter is how long a check with a backoff_max must have been
+// failing before its runs back off, when it sets no backoff_after
+const DefaultBackoffAfter = time.Hour
+
+// adapt reschedules a check after a run. A failing check runs again after
+// its failing_interval so that recovery is seen quickly. Once it has been
+// failing for backoff_after, the time between runs doubles after every run
+// up to backoff_max so that dead hosts are not probed needlessly. A passing
+// check returns to its regular schedule.
+func (e *entry) adapt(check models.Check, result models.CheckResult, now time.Time) {
+	if !result.Level.Failed() {
+		if e.mode != models.RunModeNormal {
+			e.next = e.schedule.Next(now)
+		}
+		e.mode = models.RunModeNormal
+		e.failingSince = time.Time{}
+		e.delay = 0
+		return
+	}
+
+	if e.failingSince.IsZero() {
+		e.failingSince = now
+	}
+
+	backoffAfter := time.Duration(check.BackoffAfter)
+	if backoffAfter <= 0 {
+		backoffAfter = DefaultBackoffAfter
+	}
+
+	switch {
+	case check.BackoffMax > 0 && now.Sub(e.failingSince) >= backoffAfter:
+		if e.delay == 0 {
+			e.delay = e.baseDelay(check, now)
+		}
+		// Never back off to runs more frequent than before
+		e.delay = max(e.delay, min(2*e.delay, time.Duration(check.BackoffMax)))
+		e.mode = models.RunModeBackoff
+		e.next = now.Add(e.delay)
+	case check.FailingInterval > 0:
+		e.mode = models.RunModeFast
+		e.next = now.Add(time.Duration(check.FailingInterval))
+	}
+}
+
+// baseDelay is the time between runs that backoff starts doubling from: the
+// failing_interval if the check has one, otherwise its regular interval
+func (e *entry) baseDelay(check models.Check, now time.Time) time.Duration {
+	if check.FailingInterval > 0 {
+		return time.Duration(check.FailingInterval)
+	}
+	return e.schedule.Next(now).Sub(now)
+}
+
+// RunMode returns how the check at the given index of a host is currently
+// scheduled. Checks that are disabled or not scheduled yet run normally.
+func (s *Scheduler) RunMode(hostName string, index int) models.RunMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[models.CheckID(hostName, index)]; ok {
+		return e.mode
+	}
+	return models.RunModeNormal
+}
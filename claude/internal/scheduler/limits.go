@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// targetLimiter limits how many checks run against the same target at once
+// and how soon after each other they may start
+type targetLimiter struct {
+	mu      sync.Mutex
+	targets map[string]*target
+}
+
+// target tracks the checks running against a single address or domain
+type target struct {
+	inFlight int
+	next     time.Time     // Earliest time the next check may start
+	released chan struct{} // Closed when a running check finishes
+}
+
+func newTargetLimiter() *targetLimiter {
+	return &targetLimiter{targets: make(map[string]*target)}
+}
+
+// acquire waits until fewer than concurrency checks are running against the
+// target and at least interval has passed since the previous check started.
+// A concurrency or interval of zero is not limited. The returned function
+// must be called once the check has finished.
+func (l *targetLimiter) acquire(ctx context.Context, key string, concurrency int, interval time.Duration) (func(), error) {
+	for {
+		l.mu.Lock()
+		t, ok := l.targets[key]
+		if !ok {
+			t = &target{released: make(chan struct{})}
+			l.targets[key] = t
+		}
+
+		if concurrency <= 0 || t.inFlight < concurrency {
+			start := time.Now()
+			if t.next.After(start) {
+				start = t.next
+			}
+			t.next = start.Add(interval)
+			t.inFlight++
+			l.mu.Unlock()
+
+			release := func() { l.release(key, t) }
+			if wait := time.Until(start); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					release()
+					return nil, ctx.Err()
+				}
+			}
+			return release, nil
+		}
+
+		released := t.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// release marks a check against the target as finished and wakes up the
+// checks waiting for it. Idle targets are forgotten once their interval has passed.
+func (l *targetLimiter) release(key string, t *target) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t.inFlight--
+	close(t.released)
+	t.released = make(chan struct{})
+	if t.inFlight == 0 && !t.next.After(time.Now()) {
+		delete(l.targets, key)
+	}
+}
+
+// acquire waits until a check may start: its target is within the per-target
+// limits and fewer than max_concurrent_checks checks are running in total,
+// whether scheduled or run on demand. The returned function must be called
+// once the check has finished.
+func (s *Scheduler) acquire(ctx context.Context, cfg *models.Config, j job) (func(), error) {
+	releaseTarget, err := s.targets.acquire(ctx, targetKey(j.host, j.check), cfg.TargetConcurrency, time.Duration(cfg.TargetInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		releaseTarget()
+		return nil, ctx.Err()
+	case s.slots <- struct{}{}:
+	}
+
+	return func() {
+		<-s.slots
+		releaseTarget()
+	}, nil
+}
+
+// targetKey returns the address or domain a check is run against. Checks
+// with a URL, such as HTTP checks, are keyed by the host of the URL.
+func targetKey(host models.Host, check models.Check) string {
+	if raw := check.Options["url"]; raw != "" {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	address := host.Address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		address = h
+	}
+	return strings.ToLower(address)
+}
+
+// phase returns how far into its interval the check with the given
+// models.CheckID runs. Checks that share an interval are spread across it
+// instead of all running at the same instant, and a check always gets the
+// same phase.
+func phase(checkID string, interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(checkID))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// ErrNoChecks is returned by RunNow when no enabled check matches
+var ErrNoChecks = errors.New("no enabled checks to run")
+
+// flight is a run of a check that is in progress. Callers that want the
+// result of a check that is already running wait for the flight instead of
+// starting another run.
+type flight struct {
+	done   chan struct{}
+	result models.CheckResult
+}
+
+// RunNow runs enabled checks immediately, outside their schedule, and returns
+// their results in configuration order. An empty hostName runs every check,
+// otherwise index selects the check of the host by its position and a
+// negative index runs every check of the host. Results are recorded and
+// passed to the handlers as usual. A check that is already running is not
+// started again, the result of that run is returned instead. On demand runs
+// count towards max_concurrent_checks and the per-target limits.
+//
+// If ctx is done before all checks have finished, RunNow returns the results
+// it has so far along with the context's error. The remaining checks finish
+// and are recorded in the background.
+func (s *Scheduler) RunNow(ctx context.Context, hostName string, index int) ([]models.CheckResult, error) {
+	cfg := s.source.GetConfig()
+
+	var jobs []job
+	for _, host := range cfg.Hosts {
+		if hostName != "" && host.Name != hostName {
+			continue
+		}
+		for i, check := range host.Checks {
+			if !check.Enabled || (hostName != "" && index >= 0 && i != index) {
+				continue
+			}
+			jobs = append(jobs, job{host: host, check: check, index: i})
+		}
+	}
+
+	if len(jobs) == 0 {
+		return nil, ErrNoChecks
+	}
+
+	type indexed struct {
+		i      int
+		result models.CheckResult
+	}
+	done := make(chan indexed, len(jobs))
+
+	// Runs are not tied to the caller, they are bounded by the check timeouts
+	runCtx := context.WithoutCancel(ctx)
+	for i, j := range jobs {
+		go func() {
+			done <- indexed{i: i, result: s.runShared(runCtx, cfg, j)}
+		}()
+	}
+
+	results := make([]*models.CheckResult, len(jobs))
+	var err error
+collect:
+	for range jobs {
+		select {
+		case r := <-done:
+			results[r.i] = &r.result
+		case <-ctx.Done():
+			err = ctx.Err()
+			break collect
+		}
+	}
+
+	finished := make([]models.CheckResult, 0, len(jobs))
+	for _, result := range results {
+		if result != nil {
+			finished = append(finished, *result)
+		}
+	}
+	return finished, err
+}
+
+// runShared runs a check, or waits for the run of the check that is already
+// in flight, and returns its result. ctx must not be cancelled.
+func (s *Scheduler) runShared(ctx context.Context, cfg *models.Config, j job) models.CheckResult {
+	f, leader := s.join(j)
+	if leader {
+		release, _ := s.acquire(ctx, cfg, j)
+		defer release()
+		s.lead(ctx, j, f)
+	} else {
+		<-f.done
+	}
+	return f.result
+}
+
+// join returns the flight of a check, starting a new one if the check is not
+// running. It reports true if the caller started the flight and must run it.
+func (s *Scheduler) join(j job) (*flight, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := flightKey(j)
+	if f, ok := s.flights[key]; ok {
+		return f, false
+	}
+	f := &flight{done: make(chan struct{})}
+	s.flights[key] = f
+	return f, true
+}
+
+// lead runs the check of a flight and hands the result to every caller
+// waiting for it. Scheduled runs adapt the schedule of the check when they
+// finish, on demand runs adapt it here, so a failure seen on demand is
+// followed up at the failing_interval like any other.
+func (s *Scheduler) lead(ctx context.Context, j job, f *flight) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.flights, flightKey(j))
+		if e, ok := s.entries[flightKey(j)]; ok && j.entry == nil && e.spec.fingerprint == models.CheckFingerprint(j.check) {
+			e.adapt(j.check, f.result, time.Now())
+		}
+		s.mu.Unlock()
+		close(f.done)
+	}()
+	f.result = s.record(ctx, j)
+}
+
+// flightKey identifies a check by its host and index, like the schedule entries
+func flightKey(j job) string {
+	return models.CheckID(j.host.Name, j.index)
+}
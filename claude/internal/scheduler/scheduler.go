@@ -0,0 +1,395 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/checker"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/maintenance"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/schedule"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/state"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// defaultCheckInterval is used when the configuration has no check interval
+const defaultCheckInterval = 60 * time.Second
+
+// tickInterval is how often Run looks for checks that are due
+const tickInterval = time.Second
+
+// defaultCheckTimeout is used for checks that have no timeout configured
+const defaultCheckTimeout = 5 * time.Second
+
+// timeoutGrace is added on top of a check's own timeout so that checkers
+// which enforce the timeout themselves can report their own failure message
+// before the context is cancelled
+const timeoutGrace = time.Second
+
+// ConfigSource provides the scheduler with the current configuration
+type ConfigSource interface {
+	GetConfig() *models.Config
+}
+
+// ResultHandler is called with the result of every completed check and the
+// confirmed status of the check after that result
+type ResultHandler func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status)
+
+// Scheduler runs each enabled check on its own schedule on a bounded worker pool
+type Scheduler struct {
+	registry *checker.Registry
+	source   ConfigSource
+	handlers []ResultHandler
+	inFlight sync.WaitGroup
+	tick     time.Duration
+	tracker  *state.Tracker
+	slots    chan struct{} // Bounds the checks running at once
+	targets  *targetLimiter
+	spread   bool // Spread interval checks across their interval
+
+	mu      sync.Mutex
+	entries map[string]*entry // By models.CheckID
+	flights map[string]*flight
+}
+
+// job is a single check to execute against a host
+type job struct {
+	host  models.Host
+	check models.Check
+	index int // Position of the check within the host's checks
+	entry *entry
+}
+
+// entry tracks when a single check is next due
+type entry struct {
+	spec         spec
+	schedule     schedule.Schedule
+	next         time.Time
+	running      bool
+	mode         models.RunMode
+	failingSince time.Time     // Start of the current run of failed results
+	delay        time.Duration // Time between runs while backing off
+}
+
+// spec holds the fields of a check that determine its schedule. A check
+// whose spec changes, including a check put in the place of another, is
+// rescheduled from scratch.
+type spec struct {
+	fingerprint string // models.CheckFingerprint
+	interval    models.Duration
+	schedule    string
+}
+
+// New creates a new scheduler. At most max_concurrent_checks checks of the
+// configuration run at once.
+func New(registry *checker.Registry, source ConfigSource) *Scheduler {
+	workers := source.GetConfig().MaxConcurrentChecks
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Scheduler{
+		registry: registry,
+		source:   source,
+		tick:     tickInterval,
+		tracker:  state.NewTracker(),
+		slots:    make(chan struct{}, workers),
+		targets:  newTargetLimiter(),
+		spread:   true,
+		entries:  make(map[string]*entry),
+		flights:  make(map[string]*flight),
+	}
+}
+
+// AddHandler registers a handler that receives every check result.
+// Handlers must be added before the scheduler is started.
+func (s *Scheduler) AddHandler(handler ResultHandler) {
+	s.handlers = append(s.handlers, handler)
+}
+
+// Run runs every enabled check on its schedule until the context is cancelled.
+// Interval checks first run at their phase within the first interval, cron
+// checks at their first scheduled time. A check is never started again while
+// its previous run is in flight. It returns once all in-flight checks have finished.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.inFlight.Wait()
+
+	var pending sync.WaitGroup
+	defer pending.Wait()
+
+	dispatch := func(now time.Time) {
+		cfg := s.source.GetConfig()
+		for _, j := range s.due(cfg, now) {
+			pending.Add(1)
+			go func() {
+				defer pending.Done()
+
+				var result *models.CheckResult
+				defer func() { s.finish(j, result) }()
+
+				release, err := s.acquire(ctx, cfg, j)
+				if err != nil {
+					return
+				}
+				defer release()
+
+				if ctx.Err() == nil {
+					result = s.execute(ctx, j)
+				}
+			}()
+		}
+	}
+
+	dispatch(time.Now())
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			dispatch(now)
+		}
+	}
+}
+
+// due brings the schedule in line with the configuration and returns the
+// checks that should start at the given time, marking them as running
+func (s *Scheduler) due(cfg *models.Config, now time.Time) []job {
+	defaultInterval := time.Duration(cfg.CheckInterval)
+	if defaultInterval <= 0 {
+		defaultInterval = defaultCheckInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []job
+	seen := make(map[string]bool)
+	for _, host := range cfg.Hosts {
+		for i, check := range host.Checks {
+			if !check.Enabled {
+				continue
+			}
+
+			key := models.CheckID(host.Name, i)
+			seen[key] = true
+
+			sp := spec{fingerprint: models.CheckFingerprint(check), interval: check.Interval, schedule: check.Schedule}
+			e := s.entries[key]
+			if e == nil || e.spec != sp {
+				sched, err := schedule.Parse(check, defaultInterval)
+				if err != nil {
+					log.Printf("Invalid schedule for %s/%s, using %v: %v", host.Name, check.Type, defaultInterval, err)
+					sched = schedule.Interval(defaultInterval)
+				}
+				e = &entry{spec: sp, schedule: sched, next: now}
+				// Interval checks first run at their phase within the first
+				// interval, derived from the check so it is stable across
+				// restarts, or straight away when spreading is off. Cron
+				// checks wait for their first scheduled time.
+				if !schedule.IsInterval(sched) {
+					e.next = sched.Next(now)
+				} else if s.spread {
+					e.next = now.Add(phase(models.CheckID(host.Name, i), sched.Next(now).Sub(now)))
+				}
+				s.entries[key] = e
+			}
+
+			// A schedule with no next time never runs
+			if e.running || e.next.IsZero() || now.Before(e.next) {
+				continue
+			}
+
+			// Keep a steady rhythm, but skip runs that were missed while
+			// the check was still busy
+			next := e.schedule.Next(e.next)
+			if !next.After(now) {
+				next = e.schedule.Next(now)
+			}
+			e.next = next
+			e.running = true
+
+			jobs = append(jobs, job{host: host, check: check, index: i, entry: e})
+		}
+	}
+
+	for key := range s.entries {
+		if !seen[key] {
+			delete(s.entries, key)
+		}
+	}
+	s.tracker.Prune(cfg)
+
+	return jobs
+}
+
+// finish marks a check as no longer running and adapts its schedule to the
+// result of the run, if it ran
+func (s *Scheduler) finish(j job, result *models.CheckResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.entry.running = false
+	if result != nil {
+		j.entry.adapt(j.check, *result, time.Now())
+	}
+}
+
+// NextRun returns when the check at the given index of a host is next due.
+// It returns false for checks that are disabled or have not been scheduled yet.
+func (s *Scheduler) NextRun(hostName string, index int) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[models.CheckID(hostName, index)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.next, true
+}
+
+// RunOnce runs every enabled check once and waits for them to complete.
+// If the context is cancelled, no further checks are started but checks
+// already running are allowed to finish.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	cfg := s.source.GetConfig()
+
+	var jobs []job
+	for _, host := range cfg.Hosts {
+		for i, check := range host.Checks {
+			if !check.Enabled {
+				continue
+			}
+			jobs = append(jobs, job{host: host, check: check, index: i})
+		}
+	}
+
+	if len(jobs) == 0 {
+		return
+	}
+
+	workers := min(cap(s.slots), len(jobs))
+
+	queue := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				release, err := s.acquire(ctx, cfg, j)
+				if err != nil {
+					continue
+				}
+				s.execute(ctx, j)
+				release()
+			}
+		}()
+	}
+
+dispatch:
+	for _, j := range jobs {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case queue <- j:
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// Wait blocks until all in-flight checks have finished
+func (s *Scheduler) Wait() {
+	s.inFlight.Wait()
+}
+
+// execute runs a scheduled check and returns its result. It does nothing and
+// returns nil if the check is already running on demand, as that run records
+// the result instead.
+func (s *Scheduler) execute(ctx context.Context, j job) *models.CheckResult {
+	f, leader := s.join(j)
+	if !leader {
+		return nil
+	}
+	s.lead(ctx, j, f)
+	return &f.result
+}
+
+// record runs a single check, passes the result to all handlers and returns it
+func (s *Scheduler) record(ctx context.Context, j job) models.CheckResult {
+	s.inFlight.Add(1)
+	defer s.inFlight.Done()
+
+	// Checks that have already started are not cancelled on shutdown,
+	// they are bounded by their own timeout instead
+	runCtx := context.WithoutCancel(ctx)
+
+	var result models.CheckResult
+	c, err := s.registry.Get(j.check.Type)
+	if err != nil {
+		result = models.CheckResult{
+			Host:      j.host.Name,
+			CheckType: j.check.Type,
+			Level:     models.LevelUnknown,
+			Message:   fmt.Sprintf("Check not run: %v", err),
+			Timestamp: time.Now(),
+		}
+	} else {
+		result = s.check(ctx, runCtx, c, j)
+	}
+	result.Index = j.index
+
+	// Checks keep running during maintenance and while their parents are
+	// down so their state is known as soon as that ends, but handlers see
+	// MAINTENANCE or UNREACHABLE instead of DOWN in the meantime
+	cfg := s.source.GetConfig()
+	var cond state.Conditions
+	cond.Maintenance, _ = maintenance.Active(cfg, j.host, j.check, time.Now())
+	cond.ParentDown, _ = s.tracker.ParentDown(cfg, j.host)
+	status := s.tracker.Observe(j.host.Name, j.index, j.check, result, cond)
+	for _, handler := range s.handlers {
+		handler(runCtx, j.host, j.check, result, status)
+	}
+	return result
+}
+
+// check runs a check, retrying failed attempts within the same run as
+// configured. No further retries are started once ctx is cancelled.
+func (s *Scheduler) check(ctx, runCtx context.Context, c checker.Checker, j job) models.CheckResult {
+	timeout := time.Duration(j.check.Timeout)
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	attempts := 1
+	if j.check.Retries > 0 {
+		attempts += j.check.Retries
+	}
+
+	var result models.CheckResult
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return result
+			case <-time.After(time.Duration(j.check.RetryDelay)):
+			}
+		}
+
+		checkCtx, cancel := context.WithTimeout(runCtx, timeout+timeoutGrace)
+		result = checker.ApplyLatencyThresholds(j.check, c.Check(checkCtx, j.host, j.check))
+		cancel()
+
+		if attempt > 1 {
+			result.Message = fmt.Sprintf("%s (attempt %d of %d)", result.Message, attempt, attempts)
+		}
+		if !result.Level.Failed() {
+			break
+		}
+	}
+	return result
+}
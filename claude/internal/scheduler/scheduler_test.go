@@ -0,0 +1,791 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/checker"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// fakeChecker records how many checks run concurrently
+type fakeChecker struct {
+	delay   time.Duration
+	running int32
+	peak    int32
+}
+
+func (f *fakeChecker) Type() models.CheckType {
+	return models.CheckTypePing
+}
+
+func (f *fakeChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	n := atomic.AddInt32(&f.running, 1)
+	defer atomic.AddInt32(&f.running, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return models.CheckResult{Host: host.Name, CheckType: check.Type, Message: "Check cancelled"}
+	}
+	return models.CheckResult{Host: host.Name, CheckType: check.Type, Level: models.LevelOK}
+}
+
+type staticSource struct {
+	cfg *models.Config
+}
+
+func (s staticSource) GetConfig() *models.Config {
+	return s.cfg
+}
+
+func testConfig(hosts, workers int) *models.Config {
+	cfg := &models.Config{
+		CheckInterval:       models.Duration(time.Hour),
+		MaxConcurrentChecks: workers,
+	}
+	for i := 0; i < hosts; i++ {
+		cfg.Hosts = append(cfg.Hosts, models.Host{
+			Name:    string(rune('a' + i)),
+			Address: "127.0.0.1",
+			Checks: []models.Check{
+				{Type: models.CheckTypePing, Enabled: true, Timeout: models.Duration(time.Second)},
+				{Type: models.CheckTypePing, Enabled: false},
+			},
+		})
+	}
+	return cfg
+}
+
+func TestRunOnceBoundedConcurrency(t *testing.T) {
+	fc := &fakeChecker{delay: 20 * time.Millisecond}
+	registry := checker.NewRegistry()
+	registry.Register(fc)
+
+	s := New(registry, staticSource{cfg: testConfig(8, 3)})
+
+	var mu sync.Mutex
+	var results []models.CheckResult
+	s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	})
+
+	s.RunOnce(context.Background())
+
+	if len(results) != 8 {
+		t.Errorf("Expected 8 results (disabled checks skipped), got %d", len(results))
+	}
+	if peak := atomic.LoadInt32(&fc.peak); peak > 3 {
+		t.Errorf("Expected at most 3 concurrent checks, got %d", peak)
+	}
+}
+
+func TestRunOnceUnknownCheckType(t *testing.T) {
+	cfg := &models.Config{
+		Hosts: []models.Host{
+			{
+				Name:    "test",
+				Address: "127.0.0.1",
+				Checks:  []models.Check{{Type: "bogus", Enabled: true}},
+			},
+		},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+
+	var got *models.CheckResult
+	s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		got = &result
+	})
+
+	s.RunOnce(context.Background())
+
+	if got == nil {
+		t.Fatal("Expected a result for unregistered check type")
+	}
+	if got.Level != models.LevelUnknown {
+		t.Errorf("Expected unregistered check type to be UNKNOWN, got %s", got.Level)
+	}
+}
+
+func TestRunWaitsForInFlightChecks(t *testing.T) {
+	fc := &fakeChecker{delay: 100 * time.Millisecond}
+	registry := checker.NewRegistry()
+	registry.Register(fc)
+
+	s := New(registry, staticSource{cfg: testConfig(2, 2)})
+	s.spread = false
+
+	var completed int32
+	s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		if result.Level == models.LevelOK {
+			atomic.AddInt32(&completed, 1)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	// Cancel while the first round is still running
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+
+	if n := atomic.LoadInt32(&completed); n != 2 {
+		t.Errorf("Expected in-flight checks to complete successfully, got %d", n)
+	}
+}
+
+func TestDueHonorsPerCheckSchedules(t *testing.T) {
+	cfg := &models.Config{
+		CheckInterval: models.Duration(time.Minute),
+		Hosts: []models.Host{
+			{
+				Name:    "payments",
+				Address: "127.0.0.1",
+				Checks: []models.Check{
+					{Type: models.CheckTypeHTTP, Enabled: true, Interval: models.Duration(10 * time.Second)},
+					{Type: models.CheckTypePing, Enabled: true},
+					{Type: models.CheckTypeTCP, Enabled: true, Schedule: "0 * * * *"},
+					{Type: models.CheckTypeDNS, Enabled: false},
+				},
+			},
+		},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	s.spread = false
+
+	start := time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC)
+	dueTypes := func(now time.Time) []models.CheckType {
+		var types []models.CheckType
+		for _, j := range s.due(cfg, now) {
+			types = append(types, j.check.Type)
+			s.finish(j, nil)
+		}
+		return types
+	}
+
+	// Interval checks start immediately, cron checks wait for their first slot
+	if got := dueTypes(start); len(got) != 2 || got[0] != models.CheckTypeHTTP || got[1] != models.CheckTypePing {
+		t.Errorf("Expected http and ping to run at start, got %v", got)
+	}
+	if got := dueTypes(start.Add(5 * time.Second)); len(got) != 0 {
+		t.Errorf("Expected no checks due after 5s, got %v", got)
+	}
+	if got := dueTypes(start.Add(10 * time.Second)); len(got) != 1 || got[0] != models.CheckTypeHTTP {
+		t.Errorf("Expected only http to run after 10s, got %v", got)
+	}
+	if got := dueTypes(start.Add(time.Minute)); len(got) != 2 {
+		t.Errorf("Expected http and ping to run after 1m, got %v", got)
+	}
+
+	next, ok := s.NextRun("payments", 2)
+	if !ok || !next.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Expected cron check to be next due at 13:00, got %v (%v)", next, ok)
+	}
+	if _, ok := s.NextRun("payments", 3); ok {
+		t.Error("Expected disabled check to have no next run")
+	}
+	if got := dueTypes(start.Add(30 * time.Minute)); len(got) != 3 {
+		t.Errorf("Expected all enabled checks to run at 13:00, got %v", got)
+	}
+}
+
+// neverSchedule is a schedule with no next run
+type neverSchedule struct{}
+
+func (neverSchedule) Next(time.Time) time.Time { return time.Time{} }
+
+func TestDueSkipsChecksThatNeverRun(t *testing.T) {
+	check := models.Check{Type: models.CheckTypePing, Enabled: true, Schedule: "0 0 30 2 *"}
+	cfg := &models.Config{
+		Hosts: []models.Host{{Name: "test", Address: "127.0.0.1", Checks: []models.Check{check}}},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	s.entries[models.CheckID("test", 0)] = &entry{
+		spec:     spec{fingerprint: models.CheckFingerprint(check), schedule: check.Schedule},
+		schedule: neverSchedule{},
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if got := s.due(cfg, start.Add(time.Duration(i)*time.Second)); len(got) != 0 {
+			t.Fatalf("Expected a check without a next run not to run, got %d jobs", len(got))
+		}
+	}
+}
+
+func TestDueSkipsRunningChecks(t *testing.T) {
+	cfg := &models.Config{
+		Hosts: []models.Host{
+			{
+				Name:    "test",
+				Address: "127.0.0.1",
+				Checks:  []models.Check{{Type: models.CheckTypePing, Enabled: true, Interval: models.Duration(time.Second)}},
+			},
+		},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	s.spread = false
+
+	start := time.Now()
+	jobs := s.due(cfg, start)
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 job, got %d", len(jobs))
+	}
+
+	// A slow check is not started again until it finishes, and missed
+	// runs are not made up afterwards
+	if got := s.due(cfg, start.Add(3*time.Second)); len(got) != 0 {
+		t.Errorf("Expected running check to be skipped, got %d jobs", len(got))
+	}
+	s.finish(jobs[0], nil)
+	if got := s.due(cfg, start.Add(3500*time.Millisecond)); len(got) != 1 {
+		t.Errorf("Expected finished check to run again, got %d jobs", len(got))
+	}
+	if next, _ := s.NextRun("test", 0); !next.Equal(start.Add(4500 * time.Millisecond)) {
+		t.Errorf("Expected next run one interval later, got %v", next.Sub(start))
+	}
+}
+
+func TestDueReschedulesChangedChecks(t *testing.T) {
+	cfg := &models.Config{
+		Hosts: []models.Host{
+			{
+				Name:    "test",
+				Address: "127.0.0.1",
+				Checks:  []models.Check{{Type: models.CheckTypePing, Enabled: true, Interval: models.Duration(time.Hour)}},
+			},
+		},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	s.spread = false
+
+	start := time.Now()
+	for _, j := range s.due(cfg, start) {
+		s.finish(j, nil)
+	}
+
+	cfg.Hosts[0].Checks[0].Interval = models.Duration(10 * time.Second)
+	if got := s.due(cfg, start.Add(time.Second)); len(got) != 1 {
+		t.Errorf("Expected check with a new interval to run immediately, got %d jobs", len(got))
+	}
+
+	cfg.Hosts[0].Checks[0].Enabled = false
+	s.due(cfg, start.Add(2*time.Second))
+	if _, ok := s.NextRun("test", 0); ok {
+		t.Error("Expected disabled check to be unscheduled")
+	}
+}
+
+func TestAdaptiveScheduling(t *testing.T) {
+	cfg := &models.Config{
+		Hosts: []models.Host{
+			{
+				Name:    "test",
+				Address: "127.0.0.1",
+				Checks: []models.Check{{
+					Type:            models.CheckTypePing,
+					Enabled:         true,
+					Interval:        models.Duration(time.Minute),
+					FailingInterval: models.Duration(10 * time.Second),
+					BackoffAfter:    models.Duration(time.Minute),
+					BackoffMax:      models.Duration(2 * time.Minute),
+				}},
+			},
+		},
+	}
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	s.spread = false
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		at       time.Duration
+		level    models.Level
+		wantMode models.RunMode
+		wantNext time.Duration
+	}{
+		{at: 0, level: models.LevelOK, wantMode: models.RunModeNormal, wantNext: time.Minute},
+		{at: time.Minute, level: models.LevelCritical, wantMode: models.RunModeFast, wantNext: 70 * time.Second},
+		{at: 70 * time.Second, level: models.LevelCritical, wantMode: models.RunModeFast, wantNext: 80 * time.Second},
+		// Failing for a minute, the delay doubles from the failing interval
+		{at: 2 * time.Minute, level: models.LevelCritical, wantMode: models.RunModeBackoff, wantNext: 140 * time.Second},
+		{at: 140 * time.Second, level: models.LevelCritical, wantMode: models.RunModeBackoff, wantNext: 180 * time.Second},
+		{at: 180 * time.Second, level: models.LevelCritical, wantMode: models.RunModeBackoff, wantNext: 260 * time.Second},
+		// Capped at backoff_max
+		{at: 260 * time.Second, level: models.LevelCritical, wantMode: models.RunModeBackoff, wantNext: 380 * time.Second},
+		{at: 380 * time.Second, level: models.LevelCritical, wantMode: models.RunModeBackoff, wantNext: 500 * time.Second},
+		// Back to the regular interval on recovery
+		{at: 500 * time.Second, level: models.LevelWarning, wantMode: models.RunModeNormal, wantNext: 560 * time.Second},
+		{at: 560 * time.Second, level: models.LevelCritical, wantMode: models.RunModeFast, wantNext: 570 * time.Second},
+	}
+
+	for _, step := range steps {
+		now := start.Add(step.at)
+		jobs := s.due(cfg, now)
+		if len(jobs) != 1 {
+			t.Fatalf("Expected check to be due at %v, got %d jobs", step.at, len(jobs))
+		}
+		jobs[0].entry.running = false
+		jobs[0].entry.adapt(jobs[0].check, models.CheckResult{Level: step.level}, now)
+
+		if mode := s.RunMode("test", 0); mode != step.wantMode {
+			t.Errorf("At %v: expected mode %q, got %q", step.at, step.wantMode, mode)
+		}
+		if next, _ := s.NextRun("test", 0); !next.Equal(start.Add(step.wantNext)) {
+			t.Errorf("At %v: expected next run at %v, got %v", step.at, step.wantNext, next.Sub(start))
+		}
+	}
+}
+
+func TestDueSpreadsIntervalChecks(t *testing.T) {
+	cfg := testConfig(20, 1)
+	s := New(checker.NewRegistry(), staticSource{cfg: cfg})
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	if got := s.due(cfg, start); len(got) == len(cfg.Hosts) {
+		t.Errorf("Expected checks sharing an interval not to all run at start")
+	}
+
+	first := make(map[string]time.Time)
+	distinct := make(map[time.Time]bool)
+	for _, host := range cfg.Hosts {
+		next, ok := s.NextRun(host.Name, 0)
+		if !ok || next.Before(start) || !next.Before(start.Add(time.Hour)) {
+			t.Errorf("Expected %s to first run within its interval, got %v", host.Name, next.Sub(start))
+		}
+		first[host.Name] = next
+		distinct[next] = true
+	}
+	if len(distinct) < len(cfg.Hosts)/2 {
+		t.Errorf("Expected checks to be spread across the interval, got %d distinct start times", len(distinct))
+	}
+
+	// The phase of a check does not change between schedulers
+	other := New(checker.NewRegistry(), staticSource{cfg: cfg})
+	other.due(cfg, start)
+	for _, host := range cfg.Hosts {
+		if next, _ := other.NextRun(host.Name, 0); !next.Equal(first[host.Name]) {
+			t.Errorf("Expected %s to keep its phase, got %v and %v", host.Name, first[host.Name].Sub(start), next.Sub(start))
+		}
+	}
+}
+
+func TestTargetLimiter(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		interval    time.Duration
+		wantPeak    int32
+		minElapsed  time.Duration
+	}{
+		{name: "unlimited", wantPeak: 4},
+		{name: "concurrency", concurrency: 2, wantPeak: 2},
+		{name: "interval", interval: 20 * time.Millisecond, wantPeak: 1, minElapsed: 60 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTargetLimiter()
+			var running, peak int32
+			var wg sync.WaitGroup
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					release, err := l.acquire(context.Background(), "api.example.com", tt.concurrency, tt.interval)
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+						return
+					}
+					defer release()
+					n := atomic.AddInt32(&running, 1)
+					for {
+						p := atomic.LoadInt32(&peak)
+						if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+				}()
+			}
+			wg.Wait()
+
+			if peak > tt.wantPeak {
+				t.Errorf("Expected at most %d checks at once, got %d", tt.wantPeak, peak)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("Expected checks to be spaced out over %v, took %v", tt.minElapsed, elapsed)
+			}
+		})
+	}
+
+	l := newTargetLimiter()
+	release, _ := l.acquire(context.Background(), "db", 1, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "db", 1, 0); err != context.DeadlineExceeded {
+		t.Errorf("Expected a deadline error while the target is busy, got %v", err)
+	}
+	release()
+	if _, err := l.acquire(context.Background(), "db", 1, 0); err != nil {
+		t.Errorf("Expected the target to be free after release, got %v", err)
+	}
+}
+
+func TestTargetKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		host  models.Host
+		check models.Check
+		want  string
+	}{
+		{
+			name:  "address",
+			host:  models.Host{Address: "DB.internal"},
+			check: models.Check{Type: models.CheckTypePostgres},
+			want:  "db.internal",
+		},
+		{
+			name:  "address with port",
+			host:  models.Host{Address: "10.0.0.5:6379"},
+			check: models.Check{Type: models.CheckTypeRedis},
+			want:  "10.0.0.5",
+		},
+		{
+			name:  "url",
+			host:  models.Host{Address: "api"},
+			check: models.Check{Type: models.CheckTypeHTTP, Options: map[string]string{"url": "https://API.example.com:8443/health"}},
+			want:  "api.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := targetKey(tt.host, tt.check); got != tt.want {
+				t.Errorf("Expected target %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// flakyChecker fails a number of times before it succeeds
+type flakyChecker struct {
+	failures int32
+	calls    int32
+}
+
+func (f *flakyChecker) Type() models.CheckType {
+	return models.CheckTypeHTTP
+}
+
+func (f *flakyChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	n := atomic.AddInt32(&f.calls, 1)
+	if n <= atomic.LoadInt32(&f.failures) {
+		return models.CheckResult{Host: host.Name, CheckType: check.Type, Level: models.LevelCritical, Message: "Connection refused", Timestamp: time.Now()}
+	}
+	return models.CheckResult{Host: host.Name, CheckType: check.Type, Level: models.LevelOK, Message: "OK", Timestamp: time.Now()}
+}
+
+func TestRunOnceRetriesAndTracksState(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int32
+		retries     int
+		wantCalls   int32
+		wantLevel   models.Level
+		wantMessage string
+		wantState   models.State
+	}{
+		{
+			name:        "no retries",
+			failures:    1,
+			wantCalls:   1,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Connection refused",
+			wantState:   models.StatePending,
+		},
+		{
+			name:        "succeeds on retry",
+			failures:    2,
+			retries:     2,
+			wantCalls:   3,
+			wantLevel:   models.LevelOK,
+			wantMessage: "OK (attempt 3 of 3)",
+			wantState:   models.StateUp,
+		},
+		{
+			name:        "retries exhausted",
+			failures:    5,
+			retries:     1,
+			wantCalls:   2,
+			wantLevel:   models.LevelCritical,
+			wantMessage: "Connection refused (attempt 2 of 2)",
+			wantState:   models.StatePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := &flakyChecker{failures: tt.failures}
+			registry := checker.NewRegistry()
+			registry.Register(fc)
+
+			cfg := &models.Config{
+				Hosts: []models.Host{
+					{
+						Name:    "test",
+						Address: "127.0.0.1",
+						Checks: []models.Check{{
+							Type:       models.CheckTypeHTTP,
+							Enabled:    true,
+							Retries:    tt.retries,
+							RetryDelay: models.Duration(time.Millisecond),
+							FailAfter:  2,
+						}},
+					},
+				},
+			}
+			s := New(registry, staticSource{cfg: cfg})
+
+			var gotResult models.CheckResult
+			var gotStatus models.Status
+			s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+				gotResult = result
+				gotStatus = status
+			})
+
+			s.RunOnce(context.Background())
+
+			if calls := atomic.LoadInt32(&fc.calls); calls != tt.wantCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.wantCalls, calls)
+			}
+			if gotResult.Level != tt.wantLevel {
+				t.Errorf("Expected level %s, got %s", tt.wantLevel, gotResult.Level)
+			}
+			if gotResult.Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, gotResult.Message)
+			}
+			if gotStatus.State != tt.wantState {
+				t.Errorf("Expected state %s, got %s", tt.wantState, gotStatus.State)
+			}
+		})
+	}
+}
+
+func TestRunNowCoalescesConcurrentRuns(t *testing.T) {
+	fc := &countingChecker{delay: 50 * time.Millisecond}
+	registry := checker.NewRegistry()
+	registry.Register(fc)
+
+	s := New(registry, staticSource{cfg: testConfig(2, 4)})
+	var recorded int32
+	s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		atomic.AddInt32(&recorded, 1)
+	})
+
+	var wg sync.WaitGroup
+	results := make([][]models.CheckResult, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			results[i], err = s.RunNow(context.Background(), "a", 0)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&fc.calls); calls != 1 {
+		t.Errorf("Expected concurrent runs to be coalesced into 1, got %d", calls)
+	}
+	if n := atomic.LoadInt32(&recorded); n != 1 {
+		t.Errorf("Expected the result to be recorded once, got %d", n)
+	}
+	for i, r := range results {
+		if len(r) != 1 || r[0].Host != "a" || r[0].Level != models.LevelOK {
+			t.Errorf("Caller %d: expected the result of host a, got %+v", i, r)
+		}
+	}
+}
+
+func TestRunNowChecksOfTheSameType(t *testing.T) {
+	fc := &countingChecker{delay: 50 * time.Millisecond}
+	registry := checker.NewRegistry()
+	registry.Register(fc)
+
+	cfg := testConfig(1, 4)
+	cfg.Hosts[0].Checks[1].Enabled = true
+	s := New(registry, staticSource{cfg: cfg})
+
+	var wg sync.WaitGroup
+	results := make([][]models.CheckResult, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = s.RunNow(context.Background(), "a", i)
+		}()
+	}
+	wg.Wait()
+
+	// Each check runs on its own instead of sharing the run of the other
+	if calls := atomic.LoadInt32(&fc.calls); calls != 2 {
+		t.Errorf("Expected both ping checks to run, got %d runs", calls)
+	}
+	for i, r := range results {
+		if len(r) != 1 || r[0].Index != i {
+			t.Errorf("Caller %d: expected the result of check %d, got %+v", i, i, r)
+		}
+	}
+
+	if phase(models.CheckID("a", 0), time.Hour) == phase(models.CheckID("a", 1), time.Hour) {
+		t.Error("Expected checks of the same type to get their own phase")
+	}
+}
+
+func TestRunNowSelectsChecks(t *testing.T) {
+	registry := checker.NewRegistry()
+	registry.Register(&countingChecker{})
+	s := New(registry, staticSource{cfg: testConfig(3, 2)})
+
+	tests := []struct {
+		name      string
+		host      string
+		index     int
+		wantHosts []string
+		wantErr   error
+	}{
+		{name: "everything", index: -1, wantHosts: []string{"a", "b", "c"}},
+		{name: "host", host: "b", index: -1, wantHosts: []string{"b"}},
+		{name: "check", host: "c", index: 0, wantHosts: []string{"c"}},
+		{name: "unknown host", host: "z", index: -1, wantErr: ErrNoChecks},
+		{name: "disabled check", host: "a", index: 1, wantErr: ErrNoChecks},
+		{name: "unknown check", host: "a", index: 2, wantErr: ErrNoChecks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.RunNow(context.Background(), tt.host, tt.index)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(results) != len(tt.wantHosts) {
+				t.Fatalf("Expected %d results, got %d", len(tt.wantHosts), len(results))
+			}
+			for i, result := range results {
+				if result.Host != tt.wantHosts[i] {
+					t.Errorf("Result %d: expected host %s, got %s", i, tt.wantHosts[i], result.Host)
+				}
+			}
+		})
+	}
+}
+
+func TestRunNowAdaptsSchedule(t *testing.T) {
+	registry := checker.NewRegistry()
+	registry.Register(&flakyChecker{failures: 1})
+	cfg := &models.Config{Hosts: []models.Host{{
+		Name:    "test",
+		Address: "127.0.0.1",
+		Checks: []models.Check{{
+			Type:            models.CheckTypeHTTP,
+			Enabled:         true,
+			Interval:        models.Duration(time.Hour),
+			FailingInterval: models.Duration(10 * time.Second),
+		}},
+	}}}
+	s := New(registry, staticSource{cfg: cfg})
+	s.spread = false
+	start := time.Now()
+	s.due(cfg, start)[0].entry.running = false
+
+	// A failure seen on demand brings the next scheduled run forward
+	if _, err := s.RunNow(context.Background(), "test", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next, _ := s.NextRun("test", 0)
+	if mode := s.RunMode("test", 0); mode != models.RunModeFast || next.After(time.Now().Add(10*time.Second)) {
+		t.Errorf("Expected a fast run within 10s, got %q at %v", mode, next.Sub(start))
+	}
+
+	// And a recovery seen on demand returns the check to its interval
+	if _, err := s.RunNow(context.Background(), "test", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next, _ = s.NextRun("test", 0)
+	if mode := s.RunMode("test", 0); mode != models.RunModeNormal || next.Before(start.Add(time.Hour)) {
+		t.Errorf("Expected the regular interval, got %q at %v", mode, next.Sub(start))
+	}
+}
+
+func TestRunNowTimeout(t *testing.T) {
+	fc := &countingChecker{delay: 200 * time.Millisecond}
+	registry := checker.NewRegistry()
+	registry.Register(fc)
+
+	s := New(registry, staticSource{cfg: testConfig(1, 1)})
+	var recorded int32
+	s.AddHandler(func(ctx context.Context, host models.Host, check models.Check, result models.CheckResult, status models.Status) {
+		atomic.AddInt32(&recorded, 1)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results, err := s.RunNow(ctx, "", -1)
+	if err != context.DeadlineExceeded || len(results) != 0 {
+		t.Fatalf("Expected a deadline error and no results, got %v and %d results", err, len(results))
+	}
+
+	// The check keeps running, so running it again waits for that run to be recorded
+	results, err = s.RunNow(context.Background(), "", -1)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected the result of the running check, got %v and %d results", err, len(results))
+	}
+	if calls := atomic.LoadInt32(&fc.calls); calls != 1 {
+		t.Errorf("Expected the running check to be reused, got %d runs", calls)
+	}
+	if n := atomic.LoadInt32(&recorded); n != 1 {
+		t.Errorf("Expected the timed out check to be recorded, got %d results", n)
+	}
+}
+
+// countingChecker counts how often it runs
+type countingChecker struct {
+	delay time.Duration
+	calls int32
+}
+
+func (c *countingChecker) Type() models.CheckType {
+	return models.CheckTypePing
+}
+
+func (c *countingChecker) Check(ctx context.Context, host models.Host, check models.Check) models.CheckResult {
+	atomic.AddInt32(&c.calls, 1)
+	time.Sleep(c.delay)
+	return models.CheckResult{Host: host.Name, CheckType: check.Type, Level: models.LevelOK}
+}
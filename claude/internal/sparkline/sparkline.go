@@ -0,0 +1,44 @@
+package sparkline
+
+import (
+	"strings"
+	"time"
+)
+
+// bars are the block characters used to draw a sparkline, lowest to highest
+var bars = []rune("▁▂▃▄▅▆▇█")
+
+// Generate renders the most recent width durations as a unicode sparkline.
+// Values are scaled between the minimum and maximum of the rendered window.
+func Generate(values []time.Duration, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+
+	// Only render the most recent values that fit in the width
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	spread := max - min
+	for _, v := range values {
+		idx := 0
+		if spread > 0 {
+			idx = int(int64(v-min) * int64(len(bars)-1) / int64(spread))
+		}
+		b.WriteRune(bars[idx])
+	}
+
+	return b.String()
+}
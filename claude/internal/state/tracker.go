@@ -0,0 +1,240 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// DefaultFlapWindow is used for checks that set a flap threshold but no window
+const DefaultFlapWindow = 10 * time.Minute
+
+// Tracker turns the raw results of each check into a confirmed state.
+// A check goes DOWN after fail_after consecutive failures and back UP after
+// recover_after consecutive successes. A check whose results change between
+// passing and failing more than flap_threshold times within flap_window is
+// FLAPPING until its results settle down again. While a check is in
+// maintenance its state is MAINTENANCE, and while a check is down because a
+// host or check it depends on is down its state is UNREACHABLE. Its results
+// are still tracked in both cases, so it returns to its real state straight away.
+//
+// Checks are tracked by their position within the host. A check that takes
+// the position of another, because checks were deleted or reordered, starts
+// from scratch rather than taking over the history of the old check.
+type Tracker struct {
+	mu     sync.Mutex
+	checks map[string]*tracked // By models.CheckID
+}
+
+// tracked holds the history of a single check
+type tracked struct {
+	fingerprint string // models.CheckFingerprint of the check the history belongs to
+	status      models.Status
+	state       models.State // Confirmed state ignoring flap detection
+	seen        bool
+	lastSuccess bool
+	changes     []time.Time // When the raw result changed between passing and failing
+}
+
+// Conditions are circumstances outside a check that change how its state is reported
+type Conditions struct {
+	Maintenance string // Why the check is in maintenance, empty if it is not
+	ParentDown  string // The parent host or check that is down, empty if none is
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		checks: make(map[string]*tracked),
+	}
+}
+
+// Observe records the result of the check at the given index of a host and
+// returns its resulting status
+func (t *Tracker) Observe(hostName string, index int, check models.Check, result models.CheckResult, cond Conditions) models.Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tr, ok := t.lookup(hostName, index, check)
+	if !ok {
+		tr = &tracked{
+			fingerprint: models.CheckFingerprint(check),
+			status:      models.Status{State: models.StatePending},
+			state:       models.StatePending,
+		}
+		t.checks[models.CheckID(hostName, index)] = tr
+	}
+
+	now := result.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	// WARNING results count as passing, the service still works
+	success := !result.Level.Failed()
+	if tr.seen && tr.lastSuccess != success {
+		tr.changes = append(tr.changes, now)
+	}
+	tr.seen = true
+	tr.lastSuccess = success
+	tr.changes = pruneChanges(tr.changes, check, now)
+
+	status := tr.status
+	status.Previous = status.State
+	if success {
+		status.Successes++
+		status.Failures = 0
+		if tr.state != models.StateDown || status.Successes >= atLeastOne(check.RecoverAfter) {
+			tr.state = models.StateUp
+		}
+	} else {
+		status.Failures++
+		status.Successes = 0
+		if status.Failures >= atLeastOne(check.FailAfter) {
+			tr.state = models.StateDown
+		}
+	}
+
+	status.State = tr.state
+	if check.FlapThreshold > 0 && len(tr.changes) > check.FlapThreshold {
+		status.State = models.StateFlapping
+	}
+	status.Reason = ""
+	switch {
+	case cond.Maintenance != "":
+		status.State = models.StateMaintenance
+		status.Reason = cond.Maintenance
+	case cond.ParentDown != "" && tr.state == models.StateDown:
+		status.State = models.StateUnreachable
+		status.Reason = fmt.Sprintf("%s is down", cond.ParentDown)
+	}
+	status.PrevSince = tr.status.Since
+	if status.Changed() {
+		status.Since = now
+	}
+
+	tr.status = status
+	return status
+}
+
+// lookup returns the history of the check at the given index of a host, if
+// it belongs to that check and not to one that was there before
+func (t *Tracker) lookup(hostName string, index int, check models.Check) (*tracked, bool) {
+	tr, ok := t.checks[models.CheckID(hostName, index)]
+	if !ok || tr.fingerprint != models.CheckFingerprint(check) {
+		return nil, false
+	}
+	return tr, true
+}
+
+// Prune drops the history of checks that were removed from the configuration
+// or replaced by another check
+func (t *Tracker) Prune(cfg *models.Config) {
+	fingerprints := models.CheckFingerprints(cfg)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, tr := range t.checks {
+		if fingerprint, ok := fingerprints[key]; !ok || fingerprint != tr.fingerprint {
+			delete(t.checks, key)
+		}
+	}
+}
+
+// ParentDown returns the first host or check that host depends on which is
+// down. A parent check is down once its confirmed state is DOWN, even while it
+// is itself UNREACHABLE, in maintenance or flapping. A parent host is down
+// when all of its enabled checks with a confirmed state are down.
+func (t *Tracker) ParentDown(cfg *models.Config, host models.Host) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, ref := range host.DependsOn {
+		if parent, ok := findHost(cfg, ref); ok {
+			if t.hostDown(parent) {
+				return ref, true
+			}
+			continue
+		}
+		if hostName, checkType, ok := models.SplitCheckRef(ref); ok && t.down(cfg, hostName, checkType) {
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+// hostDown reports whether all enabled checks of a host with a confirmed
+// state are down
+func (t *Tracker) hostDown(host models.Host) bool {
+	down := false
+	for i, check := range host.Checks {
+		if !check.Enabled {
+			continue
+		}
+		tr, ok := t.lookup(host.Name, i, check)
+		if !ok || tr.state == models.StatePending {
+			continue
+		}
+		if tr.state != models.StateDown {
+			return false
+		}
+		down = true
+	}
+	return down
+}
+
+// down reports whether the confirmed state of the check of a type on a host
+// is DOWN. Configurations that refer to a type the host has several checks
+// of are rejected, so the first check of the type is the one referred to.
+func (t *Tracker) down(cfg *models.Config, hostName string, checkType models.CheckType) bool {
+	host, ok := findHost(cfg, hostName)
+	if !ok {
+		return false
+	}
+	for i, check := range host.Checks {
+		if check.Type == checkType {
+			tr, ok := t.lookup(hostName, i, check)
+			return ok && tr.state == models.StateDown
+		}
+	}
+	return false
+}
+
+// findHost returns the host with the given name
+func findHost(cfg *models.Config, name string) (models.Host, bool) {
+	for _, host := range cfg.Hosts {
+		if host.Name == name {
+			return host, true
+		}
+	}
+	return models.Host{}, false
+}
+
+// pruneChanges drops state changes that have fallen out of the flap window
+func pruneChanges(changes []time.Time, check models.Check, now time.Time) []time.Time {
+	if check.FlapThreshold <= 0 {
+		return nil
+	}
+
+	window := time.Duration(check.FlapWindow)
+	if window <= 0 {
+		window = DefaultFlapWindow
+	}
+
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(changes) && !changes[i].After(cutoff) {
+		i++
+	}
+	return changes[i:]
+}
+
+// atLeastOne returns n, treating unset thresholds as 1
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,268 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+func TestTrackerThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   models.Check
+		results []bool
+		want    []models.State
+	}{
+		{
+			name:    "default thresholds follow every result",
+			check:   models.Check{Type: models.CheckTypePing},
+			results: []bool{true, false, true},
+			want:    []models.State{models.StateUp, models.StateDown, models.StateUp},
+		},
+		{
+			name:    "first failures stay pending until fail_after",
+			check:   models.Check{Type: models.CheckTypePing, FailAfter: 2},
+			results: []bool{false, false, true},
+			want:    []models.State{models.StatePending, models.StateDown, models.StateUp},
+		},
+		{
+			name:    "fail_after ignores isolated failures",
+			check:   models.Check{Type: models.CheckTypePing, FailAfter: 3},
+			results: []bool{true, false, false, true, false, false, false},
+			want: []models.State{
+				models.StateUp, models.StateUp, models.StateUp, models.StateUp,
+				models.StateUp, models.StateUp, models.StateDown,
+			},
+		},
+		{
+			name:    "recover_after requires consecutive successes",
+			check:   models.Check{Type: models.CheckTypePing, RecoverAfter: 2},
+			results: []bool{false, true, false, true, true},
+			want: []models.State{
+				models.StateDown, models.StateDown, models.StateDown, models.StateDown, models.StateUp,
+			},
+		},
+		{
+			name:    "flapping above threshold",
+			check:   models.Check{Type: models.CheckTypePing, FlapThreshold: 2, FlapWindow: models.Duration(time.Hour)},
+			results: []bool{true, false, true, false, false, false},
+			want: []models.State{
+				models.StateUp, models.StateDown, models.StateUp, models.StateFlapping,
+				models.StateFlapping, models.StateFlapping,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker()
+			start := time.Now()
+			for i, success := range tt.results {
+				result := models.CheckResult{Level: level(success), Timestamp: start.Add(time.Duration(i) * time.Minute)}
+				status := tracker.Observe("host", 0, tt.check, result, Conditions{})
+				if status.State != tt.want[i] {
+					t.Errorf("Result %d: expected %s, got %s", i, tt.want[i], status.State)
+				}
+			}
+		})
+	}
+}
+
+func TestTrackerFlappingEndsOutsideWindow(t *testing.T) {
+	check := models.Check{Type: models.CheckTypeHTTP, FlapThreshold: 2, FlapWindow: models.Duration(10 * time.Minute)}
+	tracker := NewTracker()
+	start := time.Now()
+
+	observe := func(offset time.Duration, success bool) models.Status {
+		return tracker.Observe("host", 0, check, models.CheckResult{Level: level(success), Timestamp: start.Add(offset)}, Conditions{})
+	}
+
+	observe(0, true)
+	observe(time.Minute, false)
+	observe(2*time.Minute, true)
+	if status := observe(3*time.Minute, false); status.State != models.StateFlapping {
+		t.Fatalf("Expected FLAPPING after 3 changes, got %s", status.State)
+	}
+
+	// Once the changes age out of the window the underlying state returns
+	status := observe(15*time.Minute, false)
+	if status.State != models.StateDown {
+		t.Errorf("Expected DOWN once flapping ends, got %s", status.State)
+	}
+	if status.Previous != models.StateFlapping || !status.Changed() {
+		t.Errorf("Expected a change from FLAPPING, got %s -> %s", status.Previous, status.State)
+	}
+	if !status.Since.Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("Expected Since to be the time of the change, got %v", status.Since)
+	}
+	if !status.PrevSince.Equal(start.Add(3 * time.Minute)) {
+		t.Errorf("Expected PrevSince to be when the check started flapping, got %v", status.PrevSince)
+	}
+}
+
+func TestTrackerCountsAndSince(t *testing.T) {
+	check := models.Check{Type: models.CheckTypeTCP, FailAfter: 3}
+	tracker := NewTracker()
+	start := time.Now()
+
+	tracker.Observe("host", 0, check, models.CheckResult{Level: models.LevelOK, Timestamp: start}, Conditions{})
+	status := tracker.Observe("host", 0, check, models.CheckResult{Level: models.LevelCritical, Timestamp: start.Add(time.Minute)}, Conditions{})
+	if status.State != models.StateUp || status.Failures != 1 || status.Changed() {
+		t.Errorf("Expected UP with 1 failure and no change, got %+v", status)
+	}
+	if !status.Since.Equal(start) {
+		t.Errorf("Expected Since to stay at the first success, got %v", status.Since)
+	}
+
+	// Checks are tracked per host
+	other := tracker.Observe("other", 0, check, models.CheckResult{Level: models.LevelCritical, Timestamp: start}, Conditions{})
+	if other.State != models.StatePending || other.Failures != 1 {
+		t.Errorf("Expected a separate pending status for another host, got %+v", other)
+	}
+}
+
+func TestTrackerMaintenance(t *testing.T) {
+	check := models.Check{Type: models.CheckTypeHTTP}
+	tracker := NewTracker()
+	start := time.Now()
+
+	tracker.Observe("host", 0, check, models.CheckResult{Level: models.LevelOK, Timestamp: start}, Conditions{})
+	status := tracker.Observe("host", 0, check, models.CheckResult{Level: models.LevelCritical, Timestamp: start.Add(time.Minute)}, Conditions{Maintenance: "Maintenance window patching"})
+	if status.State != models.StateMaintenance || status.Reason != "Maintenance window patching" {
+		t.Fatalf("Expected MAINTENANCE with a reason, got %+v", status)
+	}
+	if status.Failures != 1 {
+		t.Errorf("Expected failures to be counted during maintenance, got %d", status.Failures)
+	}
+
+	// Leaving maintenance reports the real state of the check
+	status = tracker.Observe("host", 0, check, models.CheckResult{Level: models.LevelCritical, Timestamp: start.Add(2 * time.Minute)}, Conditions{})
+	if status.State != models.StateDown || status.Previous != models.StateMaintenance || status.Reason != "" {
+		t.Errorf("Expected a change from MAINTENANCE to DOWN, got %+v", status)
+	}
+}
+
+func TestTrackerUnreachable(t *testing.T) {
+	router := models.Host{Name: "router", Checks: []models.Check{
+		{Type: models.CheckTypePing, Enabled: true},
+		{Type: models.CheckTypeHTTP, Enabled: true},
+	}}
+	server := models.Host{Name: "server", DependsOn: []string{"router"}, Checks: []models.Check{
+		{Type: models.CheckTypePing, Enabled: true},
+	}}
+	cfg := &models.Config{Hosts: []models.Host{router, server}}
+	tracker := NewTracker()
+	start := time.Now()
+
+	observe := func(host models.Host, index int, success bool) models.Status {
+		parent, _ := tracker.ParentDown(cfg, host)
+		return tracker.Observe(host.Name, index, host.Checks[index], models.CheckResult{Level: level(success), Timestamp: start}, Conditions{ParentDown: parent})
+	}
+
+	observe(router, 0, false)
+	observe(router, 1, true)
+	if parent, ok := tracker.ParentDown(cfg, server); ok {
+		t.Errorf("Expected the router to be up while one of its checks passes, got %s down", parent)
+	}
+	if status := observe(server, 0, false); status.State != models.StateDown {
+		t.Errorf("Expected DOWN while the router is up, got %s", status.State)
+	}
+
+	observe(router, 1, false)
+	status := observe(server, 0, false)
+	if status.State != models.StateUnreachable || status.Reason != "router is down" {
+		t.Errorf("Expected UNREACHABLE because the router is down, got %+v", status)
+	}
+
+	// Dependencies are followed through unreachable hosts and single checks
+	app := models.Host{Name: "app", DependsOn: []string{"server/ping"}, Checks: []models.Check{
+		{Type: models.CheckTypeHTTP, Enabled: true},
+	}}
+	cfg.Hosts = append(cfg.Hosts, app)
+	if status := observe(app, 0, false); status.State != models.StateUnreachable || status.Reason != "server/ping is down" {
+		t.Errorf("Expected UNREACHABLE because server/ping is down, got %+v", status)
+	}
+
+	// A passing check is UP whatever the state of its parents
+	if status := observe(server, 0, true); status.State != models.StateUp {
+		t.Errorf("Expected UP once the server passes, got %s", status.State)
+	}
+}
+
+func TestTrackerChecksOfTheSameType(t *testing.T) {
+	cache := models.Host{Name: "cache", Checks: []models.Check{
+		{Type: models.CheckTypeTCP, Enabled: true, Options: map[string]string{"port": "6379"}},
+		{Type: models.CheckTypeTCP, Enabled: true, Options: map[string]string{"port": "11211"}},
+	}}
+	app := models.Host{Name: "app", DependsOn: []string{"cache"}, Checks: []models.Check{
+		{Type: models.CheckTypeHTTP, Enabled: true},
+	}}
+	cfg := &models.Config{Hosts: []models.Host{cache, app}}
+	tracker := NewTracker()
+	start := time.Now()
+
+	tracker.Observe("cache", 0, cache.Checks[0], models.CheckResult{Level: models.LevelOK, Timestamp: start}, Conditions{})
+	status := tracker.Observe("cache", 1, cache.Checks[1], models.CheckResult{Level: models.LevelCritical, Timestamp: start}, Conditions{})
+	if status.State != models.StateDown {
+		t.Errorf("Expected the second tcp check to be DOWN, got %s", status.State)
+	}
+	status = tracker.Observe("cache", 0, cache.Checks[0], models.CheckResult{Level: models.LevelOK, Timestamp: start.Add(time.Minute)}, Conditions{})
+	if status.State != models.StateUp || status.Changed() {
+		t.Errorf("Expected the first tcp check to stay UP, got %+v", status)
+	}
+
+	// The host is not down while one of its tcp checks is up
+	if parent, ok := tracker.ParentDown(cfg, app); ok {
+		t.Errorf("Expected cache not to be down, got %q", parent)
+	}
+}
+
+func TestTrackerReplacedChecks(t *testing.T) {
+	api := models.Check{Type: models.CheckTypeHTTP, Enabled: true, Options: map[string]string{"url": "https://web-1/api"}}
+	home := models.Check{Type: models.CheckTypeHTTP, Enabled: true, Options: map[string]string{"url": "https://web-1/"}}
+	tracker := NewTracker()
+	start := time.Now()
+
+	tracker.Observe("web-1", 0, api, models.CheckResult{Level: models.LevelCritical, Timestamp: start}, Conditions{})
+	tracker.Observe("web-1", 1, home, models.CheckResult{Level: models.LevelOK, Timestamp: start}, Conditions{})
+
+	// The api check is deleted, so the home check moves to its position
+	cfg := &models.Config{Hosts: []models.Host{{Name: "web-1", Checks: []models.Check{home}}}}
+	tracker.Prune(cfg)
+	if len(tracker.checks) != 0 {
+		t.Errorf("Expected the history of both moved checks to be dropped, got %d", len(tracker.checks))
+	}
+	status := tracker.Observe("web-1", 0, home, models.CheckResult{Level: models.LevelOK, Timestamp: start.Add(time.Minute)}, Conditions{})
+	if status.Previous != models.StatePending || status.State != models.StateUp {
+		t.Errorf("Expected the home check to start from PENDING rather than the api check's DOWN, got %+v", status)
+	}
+
+	// A check is told apart from the one before it even before pruning
+	status = tracker.Observe("web-1", 0, api, models.CheckResult{Level: models.LevelOK, Timestamp: start.Add(2 * time.Minute)}, Conditions{})
+	if status.Previous != models.StatePending {
+		t.Errorf("Expected a check in the place of another to start from PENDING, got %+v", status)
+	}
+
+	// Disabling a check or changing its thresholds keeps its history
+	api.Enabled, api.FailAfter = false, 3
+	cfg.Hosts[0].Checks = []models.Check{api}
+	tracker.Prune(cfg)
+	status = tracker.Observe("web-1", 0, api, models.CheckResult{Level: models.LevelOK, Timestamp: start.Add(3 * time.Minute)}, Conditions{})
+	if status.Previous != models.StateUp || status.Successes != 2 {
+		t.Errorf("Expected the edited check to keep its history, got %+v", status)
+	}
+
+	tracker.Prune(&models.Config{})
+	if len(tracker.checks) != 0 {
+		t.Errorf("Expected the history of removed hosts to be dropped, got %d", len(tracker.checks))
+	}
+}
+
+// level converts a test outcome to a result level
+func level(success bool) models.Level {
+	if success {
+		return models.LevelOK
+	}
+	return models.LevelCritical
+}
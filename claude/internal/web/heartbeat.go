@@ -0,0 +1,72 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/heartbeat"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// maxPingBodySize is the maximum number of body bytes kept as the ping message
+const maxPingBodySize = 1024
+
+// SetHeartbeats sets the store that receives pings for heartbeat checks.
+// Ping endpoints return 404 until a store is set.
+func (s *Server) SetHeartbeats(store *heartbeat.Store) {
+	s.heartbeats = store
+}
+
+// handlePing records a heartbeat ping.
+// URL format: /ping/{token}, /ping/{token}/start or /ping/{token}/fail
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/ping/")
+	parts := strings.Split(path, "/")
+
+	signal := heartbeat.SignalSuccess
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 2 && parts[1] == "start":
+		signal = heartbeat.SignalStart
+	case len(parts) == 2 && parts[1] == "fail":
+		signal = heartbeat.SignalFail
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	token := parts[0]
+	if s.heartbeats == nil || token == "" || !s.hasHeartbeatToken(token) {
+		http.NotFound(w, r)
+		return
+	}
+
+	// The body lets jobs report what happened, e.g. the tail of their output
+	body, _ := io.ReadAll(io.LimitReader(r.Body, maxPingBodySize))
+	s.heartbeats.Record(token, signal, strings.TrimSpace(string(body)), time.Now())
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte("OK"))
+}
+
+// hasHeartbeatToken reports whether a configured heartbeat check uses token
+func (s *Server) hasHeartbeatToken(token string) bool {
+	s.configMux.RLock()
+	defer s.configMux.RUnlock()
+
+	for _, host := range s.config.Hosts {
+		for _, check := range host.Checks {
+			if check.Type == models.CheckTypeHeartbeat && check.Options["token"] == token {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/scheduler"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// runNowTimeout is how long a run now request waits for check results
+const runNowTimeout = 30 * time.Second
+
+// CheckRunner runs checks on demand, outside their schedule. An empty host
+// runs every check and a negative index every check of the host.
+type CheckRunner interface {
+	RunNow(ctx context.Context, hostName string, index int) ([]models.CheckResult, error)
+}
+
+// SetRunner sets the runner used by the run now endpoints.
+// They return 503 until a runner is set.
+func (s *Server) SetRunner(runner CheckRunner) {
+	s.runner = runner
+}
+
+// handleRunNow runs a check, all checks of a host or, without a host, every
+// check and waits for the results. HTMX requests get the updated hosts list,
+// other requests the results as JSON. Checks that take longer than
+// runNowTimeout are left to finish in the background.
+func (s *Server) handleRunNow(w http.ResponseWriter, r *http.Request, hostName string, index int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.runner == nil {
+		http.Error(w, "Running checks on demand is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), runNowTimeout)
+	defer cancel()
+
+	code := http.StatusOK
+	results, err := s.runner.RunNow(ctx, hostName, index)
+	switch {
+	case errors.Is(err, scheduler.ErrNoChecks):
+		http.Error(w, "No enabled checks found", http.StatusNotFound)
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		code = http.StatusGatewayTimeout
+	case err != nil:
+		log.Printf("Failed to run checks: %v", err)
+		http.Error(w, "Failed to run checks", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") != "" {
+		// The dashboard shows whatever has been recorded so far
+		s.handleGetHosts(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(results)
+}
@@ -9,12 +9,14 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/andrewsjg/simple-healthchecker/claude/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/heartbeat"
 	"github.com/andrewsjg/simple-healthchecker/claude/internal/sparkline"
 	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
 )
@@ -25,27 +27,43 @@ var templatesFS embed.FS
 // HostStatus represents the status of a host with check results
 type HostStatus struct {
 	models.Host
-	Checks []CheckStatus
+	Checks       []CheckStatus
+	Dependencies []string // Each chain of parents the host depends on, nearest first
 }
 
 // CheckStatus represents a check with its last result and latency history
 type CheckStatus struct {
 	models.Check
+	Index            int // Position of the check within the host, used in API paths
 	LastResult       *models.CheckResult
 	LatencySparkline string
+	NextRun          time.Time
+	RunMode          models.RunMode
+	Status           models.Status
+}
+
+// NextRunSource reports when checks are next due to run and whether they
+// are re-checked faster or backed off because they are failing
+type NextRunSource interface {
+	NextRun(hostName string, index int) (time.Time, bool)
+	RunMode(hostName string, index int) models.RunMode
 }
 
 // Server represents the web server
 type Server struct {
-	config          *models.Config
-	configPath      string
-	port            int
-	results         map[string]map[models.CheckType]*models.CheckResult
-	latencyHistory  map[string]map[models.CheckType][]time.Duration
-	maxHistorySize  int
-	resultsMux      sync.RWMutex
-	configMux       sync.RWMutex
-	templates       *template.Template
+	config         *models.Config
+	configPath     string
+	port           int
+	results        map[string]map[int]*models.CheckResult // By host, then check index
+	statuses       map[string]map[int]models.Status
+	latencyHistory map[string]map[int][]time.Duration
+	maxHistorySize int
+	resultsMux     sync.RWMutex
+	configMux      sync.RWMutex
+	templates      *template.Template
+	heartbeats     *heartbeat.Store
+	nextRuns       NextRunSource
+	runner         CheckRunner
 }
 
 // NewServer creates a new web server
@@ -59,6 +77,8 @@ func NewServer(config *models.Config, configPath string, port int) (*Server, err
 			}
 			return string(b), nil
 		},
+		"extraOptions": formatExtraOptions,
+		"join":         strings.Join,
 	})
 
 	tmpl, err := tmpl.ParseFS(templatesFS, "templates/*.html")
@@ -70,29 +90,34 @@ func NewServer(config *models.Config, configPath string, port int) (*Server, err
 		config:         config,
 		configPath:     configPath,
 		port:           port,
-		results:        make(map[string]map[models.CheckType]*models.CheckResult),
-		latencyHistory: make(map[string]map[models.CheckType][]time.Duration),
+		results:        make(map[string]map[int]*models.CheckResult),
+		statuses:       make(map[string]map[int]models.Status),
+		latencyHistory: make(map[string]map[int][]time.Duration),
 		maxHistorySize: 50, // Store last 50 measurements for sparkline
 		templates:      tmpl,
 	}, nil
 }
 
-// UpdateResult updates the result for a host/check and maintains latency history
-func (s *Server) UpdateResult(result models.CheckResult) {
+// UpdateResult updates the result and confirmed status for a host/check and
+// maintains latency history. Checks are told apart by their index, so a host
+// can have several checks of the same type.
+func (s *Server) UpdateResult(result models.CheckResult, status models.Status) {
 	s.resultsMux.Lock()
 	defer s.resultsMux.Unlock()
 
 	if s.results[result.Host] == nil {
-		s.results[result.Host] = make(map[models.CheckType]*models.CheckResult)
+		s.results[result.Host] = make(map[int]*models.CheckResult)
+		s.statuses[result.Host] = make(map[int]models.Status)
 	}
-	s.results[result.Host][result.CheckType] = &result
+	s.results[result.Host][result.Index] = &result
+	s.statuses[result.Host][result.Index] = status
 
 	// Update latency history
 	if s.latencyHistory[result.Host] == nil {
-		s.latencyHistory[result.Host] = make(map[models.CheckType][]time.Duration)
+		s.latencyHistory[result.Host] = make(map[int][]time.Duration)
 	}
 
-	history := s.latencyHistory[result.Host][result.CheckType]
+	history := s.latencyHistory[result.Host][result.Index]
 	history = append(history, result.Duration)
 
 	// Keep only the last maxHistorySize measurements
@@ -100,7 +125,12 @@ func (s *Server) UpdateResult(result models.CheckResult) {
 		history = history[len(history)-s.maxHistorySize:]
 	}
 
-	s.latencyHistory[result.Host][result.CheckType] = history
+	s.latencyHistory[result.Host][result.Index] = history
+}
+
+// SetNextRuns sets the source of the next scheduled run shown for each check
+func (s *Server) SetNextRuns(source NextRunSource) {
+	s.nextRuns = source
 }
 
 // Start starts the web server
@@ -117,11 +147,18 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	})
 	mux.HandleFunc("/api/hosts/", s.handleAPIRoutes)
+	mux.HandleFunc("/api/run", func(w http.ResponseWriter, r *http.Request) {
+		s.handleRunNow(w, r, "", -1)
+	})
 	mux.HandleFunc("/api/host/add", s.handleAddHost)
 	mux.HandleFunc("/api/host/edit", s.handleEditHost)
 	mux.HandleFunc("/api/host/delete", s.handleDeleteHost)
 	mux.HandleFunc("/api/host/add-form", s.handleGetAddForm)
 	mux.HandleFunc("/api/host/edit-form", s.handleGetEditForm)
+	mux.HandleFunc("/api/silences", s.handleSilences)
+	mux.HandleFunc("/api/silences/", s.handleDeleteSilence)
+	mux.HandleFunc("/api/silence-form", s.handleGetSilenceForm)
+	mux.HandleFunc("/ping/", s.handlePing)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.port),
@@ -159,24 +196,34 @@ func (s *Server) handleGetHosts(w http.ResponseWriter, r *http.Request) {
 	var hostStatuses []HostStatus
 	for _, host := range s.config.Hosts {
 		status := HostStatus{
-			Host:   host,
-			Checks: make([]CheckStatus, 0, len(host.Checks)),
+			Host:         host,
+			Checks:       make([]CheckStatus, 0, len(host.Checks)),
+			Dependencies: dependencyChains(s.config.Hosts, host),
 		}
 
-		for _, check := range host.Checks {
+		for i, check := range host.Checks {
 			checkStatus := CheckStatus{
 				Check: check,
+				Index: i,
+			}
+
+			if s.nextRuns != nil {
+				if next, ok := s.nextRuns.NextRun(host.Name, i); ok {
+					checkStatus.NextRun = next
+				}
+				checkStatus.RunMode = s.nextRuns.RunMode(host.Name, i)
 			}
 
 			if hostResults, ok := s.results[host.Name]; ok {
-				if result, ok := hostResults[check.Type]; ok {
+				if result, ok := hostResults[i]; ok && result.CheckType == check.Type {
 					checkStatus.LastResult = result
+					checkStatus.Status = s.statuses[host.Name][i]
 				}
 			}
 
 			// Generate sparkline from latency history
 			if historyMap, ok := s.latencyHistory[host.Name]; ok {
-				if history, ok := historyMap[check.Type]; ok && len(history) > 0 {
+				if history, ok := historyMap[i]; ok && len(history) > 0 {
 					checkStatus.LatencySparkline = sparkline.Generate(history, 30)
 				}
 			}
@@ -187,10 +234,16 @@ func (s *Server) handleGetHosts(w http.ResponseWriter, r *http.Request) {
 		hostStatuses = append(hostStatuses, status)
 	}
 
+	s.configMux.RLock()
+	silences := activeSilences(s.config.Silences, time.Now())
+	s.configMux.RUnlock()
+
 	data := struct {
-		Hosts []HostStatus
+		Hosts    []HostStatus
+		Silences []models.Silence
 	}{
-		Hosts: hostStatuses,
+		Hosts:    hostStatuses,
+		Silences: silences,
 	}
 
 	// Set Content-Type before writing
@@ -204,14 +257,27 @@ func (s *Server) handleGetHosts(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleAPIRoutes(w http.ResponseWriter, r *http.Request) {
-	// Parse URL: /api/hosts/{hostName}/checks/{checkType}/{action}
+	// Parse URL: /api/hosts/{hostName}/checks/{check}/{action} or /api/hosts/{hostName}/run
 	path := strings.TrimPrefix(r.URL.Path, "/api/hosts/")
 	parts := strings.Split(path, "/")
 
+	if len(parts) == 4 && parts[1] == "checks" && parts[3] == "run" {
+		index, ok := s.lookupCheck(parts[0], parts[2])
+		if !ok {
+			http.Error(w, "Host or check not found", http.StatusNotFound)
+			return
+		}
+		s.handleRunNow(w, r, parts[0], index)
+		return
+	}
 	if len(parts) == 4 && parts[1] == "checks" {
 		s.handleCheckToggle(w, r)
 		return
 	}
+	if len(parts) == 2 && parts[1] == "run" {
+		s.handleRunNow(w, r, parts[0], -1)
+		return
+	}
 
 	http.NotFound(w, r)
 }
@@ -222,7 +288,7 @@ func (s *Server) handleCheckToggle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse URL: /api/hosts/{hostName}/checks/{checkType}/{action}
+	// Parse URL: /api/hosts/{hostName}/checks/{check}/{action}
 	path := strings.TrimPrefix(r.URL.Path, "/api/hosts/")
 	parts := strings.Split(path, "/")
 	if len(parts) != 4 || parts[1] != "checks" {
@@ -231,7 +297,6 @@ func (s *Server) handleCheckToggle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	hostName := parts[0]
-	checkType := models.CheckType(parts[2])
 	action := parts[3]
 
 	if action != "enable" && action != "disable" {
@@ -241,18 +306,17 @@ func (s *Server) handleCheckToggle(w http.ResponseWriter, r *http.Request) {
 
 	// Find the host and check
 	var found bool
+	s.configMux.Lock()
 	for i, host := range s.config.Hosts {
 		if host.Name == hostName {
-			for j, check := range host.Checks {
-				if check.Type == checkType {
-					s.config.Hosts[i].Checks[j].Enabled = (action == "enable")
-					found = true
-					break
-				}
+			if j, ok := checkIndex(host, parts[2]); ok {
+				s.config.Hosts[i].Checks[j].Enabled = (action == "enable")
+				found = true
 			}
 			break
 		}
 	}
+	s.configMux.Unlock()
 
 	if !found {
 		http.Error(w, "Host or check not found", http.StatusNotFound)
@@ -263,11 +327,52 @@ func (s *Server) handleCheckToggle(w http.ResponseWriter, r *http.Request) {
 	s.handleGetHosts(w, r)
 }
 
-// GetConfig returns the current configuration (thread-safe)
+// checkIndex resolves the check segment of an API path: the index of the
+// check within the host or, for older clients, its type, which selects the
+// first check of that type
+func checkIndex(host models.Host, segment string) (int, bool) {
+	if i, err := strconv.Atoi(segment); err == nil {
+		return i, i >= 0 && i < len(host.Checks)
+	}
+	for i, check := range host.Checks {
+		if check.Type == models.CheckType(segment) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// lookupCheck returns the index of the check a path segment refers to in the
+// host with the given name
+func (s *Server) lookupCheck(hostName, segment string) (int, bool) {
+	s.configMux.RLock()
+	defer s.configMux.RUnlock()
+	for _, host := range s.config.Hosts {
+		if host.Name == hostName {
+			return checkIndex(host, segment)
+		}
+	}
+	return 0, false
+}
+
+// GetConfig returns a snapshot of the current configuration (thread-safe)
 func (s *Server) GetConfig() *models.Config {
 	s.configMux.RLock()
 	defer s.configMux.RUnlock()
-	return s.config
+
+	cfg := *s.config
+	cfg.Hosts = make([]models.Host, len(s.config.Hosts))
+	for i, host := range s.config.Hosts {
+		host.Tags = append([]string(nil), host.Tags...)
+		host.DependsOn = append([]string(nil), host.DependsOn...)
+		host.Notify = append([]string(nil), host.Notify...)
+		host.Checks = append([]models.Check(nil), host.Checks...)
+		cfg.Hosts[i] = host
+	}
+	cfg.Maintenance = append([]models.MaintenanceWindow(nil), s.config.Maintenance...)
+	cfg.Silences = append([]models.Silence(nil), s.config.Silences...)
+	cfg.Notifications = append([]models.Channel(nil), s.config.Notifications...)
+	return &cfg
 }
 
 // saveConfig saves the current configuration to disk
@@ -324,16 +429,37 @@ func (s *Server) handleAddHost(w http.ResponseWriter, r *http.Request) {
 
 	// Parse checks from form
 	checks := parseChecksFromForm(r)
+	if err := validateChecks(checks); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
 
 	// Create new host
 	newHost := models.Host{
-		Name:    hostName,
-		Address: hostAddress,
-		Checks:  checks,
+		Name:      hostName,
+		Address:   hostAddress,
+		Tags:      parseList(r.FormValue("tags")),
+		DependsOn: parseList(r.FormValue("depends_on")),
+		Notify:    parseList(r.FormValue("notify")),
+		Checks:    checks,
+	}
+
+	hosts := append(append([]models.Host(nil), s.config.Hosts...), newHost)
+	if err := config.ValidateHeartbeats(hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := config.ValidateDependencies(hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := config.ValidateNotifications(s.config.Notifications, hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
 	}
 
 	// Add host to config
-	s.config.Hosts = append(s.config.Hosts, newHost)
+	s.config.Hosts = hosts
 
 	// Save configuration
 	if err := config.SaveConfig(s.configPath, s.config); err != nil {
@@ -398,11 +524,45 @@ func (s *Server) handleEditHost(w http.ResponseWriter, r *http.Request) {
 
 	// Parse checks from form
 	checks := parseChecksFromForm(r)
+	if err := validateChecks(checks); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
 
-	// Update host
-	s.config.Hosts[hostIndex].Name = hostName
-	s.config.Hosts[hostIndex].Address = hostAddress
-	s.config.Hosts[hostIndex].Checks = checks
+	// Update a copy of the hosts so nothing changes if the dependencies are invalid.
+	// Dependencies on the host follow it when it is renamed.
+	hosts := make([]models.Host, len(s.config.Hosts))
+	for i, host := range s.config.Hosts {
+		host.DependsOn = renameDependencies(host.DependsOn, originalName, hostName)
+		hosts[i] = host
+	}
+	hosts[hostIndex].Name = hostName
+	hosts[hostIndex].Address = hostAddress
+	hosts[hostIndex].Tags = parseList(r.FormValue("tags"))
+	hosts[hostIndex].DependsOn = parseList(r.FormValue("depends_on"))
+	hosts[hostIndex].Notify = parseList(r.FormValue("notify"))
+	hosts[hostIndex].Checks = checks
+
+	if err := config.ValidateHeartbeats(hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := config.ValidateDependencies(hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := config.ValidateNotifications(s.config.Notifications, hosts); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+	s.config.Hosts = hosts
+
+	// Silences follow the host when it is renamed
+	for i := range s.config.Silences {
+		if s.config.Silences[i].Host == originalName {
+			s.config.Silences[i].Host = hostName
+		}
+	}
 
 	// Save configuration
 	if err := config.SaveConfig(s.configPath, s.config); err != nil {
@@ -453,8 +613,11 @@ func (s *Server) handleDeleteHost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Remove host from slice
+	// Remove host from slice, along with any dependencies on it
 	s.config.Hosts = append(s.config.Hosts[:hostIndex], s.config.Hosts[hostIndex+1:]...)
+	for i, host := range s.config.Hosts {
+		s.config.Hosts[i].DependsOn = renameDependencies(host.DependsOn, hostName, "")
+	}
 
 	// Save configuration
 	if err := config.SaveConfig(s.configPath, s.config); err != nil {
@@ -480,6 +643,19 @@ func parseChecksFromForm(r *http.Request) []models.Check {
 	checkHealthcheckURLs := r.Form["check_healthcheck_url[]"]
 	checkHTTPURLs := r.Form["check_http_url[]"]
 	checkHTTPStatuses := r.Form["check_http_status[]"]
+	checkSchedules := r.Form["check_schedule[]"]
+	checkFailAfter := r.Form["check_fail_after[]"]
+	checkRecoverAfter := r.Form["check_recover_after[]"]
+	checkRetries := r.Form["check_retries[]"]
+	checkRetryDelays := r.Form["check_retry_delay[]"]
+	checkFlapThresholds := r.Form["check_flap_threshold[]"]
+	checkFlapWindows := r.Form["check_flap_window[]"]
+	checkWarn := r.Form["check_warn[]"]
+	checkCritical := r.Form["check_critical[]"]
+	checkFailingIntervals := r.Form["check_failing_interval[]"]
+	checkBackoffAfter := r.Form["check_backoff_after[]"]
+	checkBackoffMax := r.Form["check_backoff_max[]"]
+	checkOptions := r.Form["check_options[]"]
 
 	for i := 0; i < len(checkTypes); i++ {
 		if checkTypes[i] == "" {
@@ -503,8 +679,13 @@ func parseChecksFromForm(r *http.Request) []models.Check {
 			healthcheckURL = checkHealthcheckURLs[i]
 		}
 
-		// Parse HTTP-specific options
+		// Parse generic key=value options
 		options := make(map[string]string)
+		if i < len(checkOptions) {
+			options = parseExtraOptions(checkOptions[i])
+		}
+
+		// Parse HTTP-specific options
 		if checkTypes[i] == "http" {
 			if i < len(checkHTTPURLs) && checkHTTPURLs[i] != "" {
 				options["url"] = checkHTTPURLs[i]
@@ -524,6 +705,28 @@ func parseChecksFromForm(r *http.Request) []models.Check {
 			Options:          options,
 		}
 
+		// A schedule is either an interval such as 30s or a cron expression
+		if i < len(checkSchedules) {
+			schedule := strings.TrimSpace(checkSchedules[i])
+			if d, err := time.ParseDuration(schedule); err == nil {
+				check.Interval = models.Duration(d)
+			} else {
+				check.Schedule = schedule
+			}
+		}
+
+		check.FailAfter = formInt(checkFailAfter, i)
+		check.RecoverAfter = formInt(checkRecoverAfter, i)
+		check.Retries = formInt(checkRetries, i)
+		check.RetryDelay = formDuration(checkRetryDelays, i)
+		check.FlapThreshold = formInt(checkFlapThresholds, i)
+		check.FlapWindow = formDuration(checkFlapWindows, i)
+		check.Warn = formDuration(checkWarn, i)
+		check.Critical = formDuration(checkCritical, i)
+		check.FailingInterval = formDuration(checkFailingIntervals, i)
+		check.BackoffAfter = formDuration(checkBackoffAfter, i)
+		check.BackoffMax = formDuration(checkBackoffMax, i)
+
 		checks = append(checks, check)
 	}
 
@@ -539,6 +742,135 @@ func parseChecksFromForm(r *http.Request) []models.Check {
 	return checks
 }
 
+// parseList parses a comma-separated form field such as host tags
+func parseList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formInt returns the i-th value of a numeric form field, or 0 when it is
+// missing or empty
+func formInt(values []string, i int) int {
+	if i >= len(values) {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(values[i]))
+	return n
+}
+
+// formDuration returns the i-th value of a duration form field such as 30s,
+// or 0 when it is missing or empty
+func formDuration(values []string, i int) models.Duration {
+	if i >= len(values) {
+		return 0
+	}
+	d, _ := time.ParseDuration(strings.TrimSpace(values[i]))
+	return models.Duration(d)
+}
+
+// validateChecks checks the schedule and threshold settings of each check
+func validateChecks(checks []models.Check) error {
+	for _, check := range checks {
+		if err := config.ValidateCheck(check); err != nil {
+			return fmt.Errorf("%s check: %w", check.Type, err)
+		}
+	}
+	return nil
+}
+
+// dependencyChains returns each chain of parents a host depends on, such as
+// "Office Switch → Office Router"
+func dependencyChains(hosts []models.Host, host models.Host) []string {
+	byName := make(map[string]models.Host, len(hosts))
+	for _, h := range hosts {
+		byName[h.Name] = h
+	}
+
+	var chains []string
+	var walk func(h models.Host, path []string)
+	walk = func(h models.Host, path []string) {
+		for _, ref := range h.DependsOn {
+			chain := append(path[:len(path):len(path)], ref)
+
+			parentName := ref
+			if _, ok := byName[ref]; !ok {
+				parentName, _, _ = models.SplitCheckRef(ref)
+			}
+			parent, ok := byName[parentName]
+			// The depth limit guards against cycles, which the loader rejects
+			if !ok || len(parent.DependsOn) == 0 || len(chain) > len(hosts) {
+				chains = append(chains, strings.Join(chain, " → "))
+				continue
+			}
+			walk(parent, chain)
+		}
+	}
+	walk(host, nil)
+	return chains
+}
+
+// renameDependencies updates dependencies on a host, or on one of its checks,
+// when the host is renamed. An empty newName removes them.
+func renameDependencies(deps []string, oldName, newName string) []string {
+	var renamed []string
+	for _, ref := range deps {
+		if ref == oldName {
+			ref = newName
+		} else if hostName, checkType, ok := models.SplitCheckRef(ref); ok && hostName == oldName {
+			ref = ""
+			if newName != "" {
+				ref = newName + "/" + string(checkType)
+			}
+		}
+		if ref != "" {
+			renamed = append(renamed, ref)
+		}
+	}
+	return renamed
+}
+
+// formatExtraOptions renders the options of a check that have no dedicated
+// form field as key=value lines, escaping control characters
+func formatExtraOptions(check models.Check) string {
+	keys := make([]string, 0, len(check.Options))
+	for key := range check.Options {
+		if check.Type == models.CheckTypeHTTP && (key == "url" || key == "expected_status") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted := strconv.Quote(check.Options[key])
+		lines = append(lines, key+"="+quoted[1:len(quoted)-1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseExtraOptions parses key=value lines produced by formatExtraOptions
+func parseExtraOptions(text string) map[string]string {
+	options := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(`"` + value + `"`); err == nil {
+			value = unquoted
+		}
+		options[strings.TrimSpace(key)] = value
+	}
+	return options
+}
+
 // handleGetAddForm returns the add host form
 func (s *Server) handleGetAddForm(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
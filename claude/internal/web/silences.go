@@ -0,0 +1,220 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/claude/internal/maintenance"
+	"github.com/andrewsjg/simple-healthchecker/claude/pkg/models"
+)
+
+// expiresLayout is the format of datetime-local inputs, interpreted in local time
+const expiresLayout = "2006-01-02T15:04"
+
+// handleSilences lists active silences or creates a new one
+func (s *Server) handleSilences(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.configMux.RLock()
+		silences := activeSilences(s.config.Silences, time.Now())
+		s.configMux.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(silences)
+	case http.MethodPost:
+		s.handleCreateSilence(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreateSilence silences a host, or a single check of a host, until
+// the silence expires. The expiry is given as a duration such as 2h or as an
+// absolute time.
+func (s *Server) handleCreateSilence(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	hostName := r.FormValue("host")
+	checkType := models.CheckType(r.FormValue("check"))
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if hostName == "" || reason == "" {
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	expires, err := silenceExpiry(r.FormValue("duration"), r.FormValue("expires"), now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %v", err), http.StatusBadRequest)
+		return
+	}
+
+	s.configMux.Lock()
+	defer s.configMux.Unlock()
+
+	if !s.hasCheck(hostName, checkType) {
+		http.Error(w, "Host or check not found", http.StatusNotFound)
+		return
+	}
+
+	silence, err := maintenance.NewSilence(hostName, checkType, reason, now, expires)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.config.Silences = append(activeSilences(s.config.Silences, now), silence)
+
+	if err := config.SaveConfig(s.configPath, s.config); err != nil {
+		log.Printf("Failed to save configuration: %v", err)
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(silence)
+		return
+	}
+
+	// Return updated hosts list
+	s.configMux.Unlock()
+	s.handleGetHosts(w, r)
+	s.configMux.Lock()
+}
+
+// handleDeleteSilence removes a silence before it expires.
+// URL format: /api/silences/{id}
+func (s *Server) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/silences/")
+
+	s.configMux.Lock()
+	defer s.configMux.Unlock()
+
+	silences := activeSilences(s.config.Silences, time.Now())
+	found := false
+	for i, silence := range silences {
+		if silence.ID == id {
+			silences = append(silences[:i], silences[i+1:]...)
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		http.Error(w, "Silence not found", http.StatusNotFound)
+		return
+	}
+	s.config.Silences = silences
+
+	if err := config.SaveConfig(s.configPath, s.config); err != nil {
+		log.Printf("Failed to save configuration: %v", err)
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Return updated hosts list
+	s.configMux.Unlock()
+	s.handleGetHosts(w, r)
+	s.configMux.Lock()
+}
+
+// handleGetSilenceForm returns the form for silencing a host or check
+func (s *Server) handleGetSilenceForm(w http.ResponseWriter, r *http.Request) {
+	hostName := r.URL.Query().Get("host")
+	if hostName == "" {
+		http.Error(w, "Missing host name", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	data := struct {
+		Host  string
+		Check string
+	}{
+		Host:  hostName,
+		Check: r.URL.Query().Get("check"),
+	}
+
+	if err := s.templates.ExecuteTemplate(w, "silence-form.html", data); err != nil {
+		log.Printf("Error rendering silence form: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// hasCheck reports whether a host exists and, if checkType is set, whether
+// it has a check of that type. The caller must hold configMux.
+func (s *Server) hasCheck(hostName string, checkType models.CheckType) bool {
+	for _, host := range s.config.Hosts {
+		if host.Name != hostName {
+			continue
+		}
+		if checkType == "" {
+			return true
+		}
+		for _, check := range host.Checks {
+			if check.Type == checkType {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// silenceExpiry returns when a new silence expires, from either an absolute
+// time or a duration. The absolute time wins when both are set.
+func silenceExpiry(duration, expires string, now time.Time) (time.Time, error) {
+	if expires = strings.TrimSpace(expires); expires != "" {
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			if t, err = time.ParseInLocation(expiresLayout, expires, time.Local); err != nil {
+				return time.Time{}, fmt.Errorf("expiry time %q", expires)
+			}
+		}
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("expiry time %q, it is in the past", expires)
+		}
+		return t, nil
+	}
+
+	duration = strings.TrimSpace(duration)
+	if duration == "" {
+		return time.Time{}, fmt.Errorf("expiry, set a duration or an expiry time")
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("duration %q", duration)
+	}
+	return now.Add(d), nil
+}
+
+// activeSilences returns the silences that have not expired yet
+func activeSilences(silences []models.Silence, now time.Time) []models.Silence {
+	active := make([]models.Silence, 0, len(silences))
+	for _, silence := range silences {
+		if silence.Active(now) {
+			active = append(active, silence)
+		}
+	}
+	return active
+}
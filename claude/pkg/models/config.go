@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -34,17 +37,72 @@ func (d Duration) Seconds() int {
 
 // Config represents the application configuration
 type Config struct {
-	Hosts            []Host   `yaml:"hosts" toml:"hosts"`
-	CheckInterval    Duration `yaml:"check_interval" toml:"check_interval"`
-	WebServerPort    int      `yaml:"web_server_port" toml:"web_server_port"`
-	EnableConsoleLog bool     `yaml:"enable_console_log" toml:"enable_console_log"`
+	Hosts               []Host              `yaml:"hosts" toml:"hosts"`
+	CheckInterval       Duration            `yaml:"check_interval" toml:"check_interval"`
+	WebServerPort       int                 `yaml:"web_server_port" toml:"web_server_port"`
+	EnableConsoleLog    bool                `yaml:"enable_console_log" toml:"enable_console_log"`
+	MaxConcurrentChecks int                 `yaml:"max_concurrent_checks,omitempty" toml:"max_concurrent_checks,omitempty"`
+	TargetConcurrency   int                 `yaml:"target_concurrency,omitempty" toml:"target_concurrency,omitempty"` // Checks run at once against one address or domain
+	TargetInterval      Duration            `yaml:"target_interval,omitempty" toml:"target_interval,omitempty"`       // Time between check starts against one address or domain
+	Maintenance         []MaintenanceWindow `yaml:"maintenance,omitempty" toml:"maintenance,omitempty"`
+	Silences            []Silence           `yaml:"silences,omitempty" toml:"silences,omitempty"`
+	Notifications       []Channel           `yaml:"notifications,omitempty" toml:"notifications,omitempty"`
+	DashboardURL        string              `yaml:"dashboard_url,omitempty" toml:"dashboard_url,omitempty"` // Address of the dashboard linked from notifications
 }
 
 // Host represents a host to monitor
 type Host struct {
-	Name    string  `yaml:"name" toml:"name"`
-	Address string  `yaml:"address" toml:"address"`
-	Checks  []Check `yaml:"checks" toml:"checks"`
+	Name      string   `yaml:"name" toml:"name"`
+	Address   string   `yaml:"address" toml:"address"`
+	Tags      []string `yaml:"tags,omitempty" toml:"tags,omitempty"`
+	DependsOn []string `yaml:"depends_on,omitempty" toml:"depends_on,omitempty"` // Parent hosts, or single checks written as "host/type"
+	Notify    []string `yaml:"notify,omitempty" toml:"notify,omitempty"`         // Names of the notification channels told about state changes
+	Checks    []Check  `yaml:"checks" toml:"checks"`
+}
+
+// SplitCheckRef splits a reference to a single check, written as "host/type"
+func SplitCheckRef(ref string) (string, CheckType, bool) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:i], CheckType(ref[i+1:]), true
+}
+
+// CheckID identifies a check by its host and its position within the host's
+// checks, so that checks of the same type on one host are told apart
+func CheckID(hostName string, index int) string {
+	return fmt.Sprintf("%s#%d", hostName, index)
+}
+
+// CheckFingerprint identifies what a check tests: its type and options. A
+// check put in the place of another, by deleting or reordering checks, has a
+// different fingerprint, while enabling, disabling or tuning the thresholds
+// of a check keeps it.
+func CheckFingerprint(check Check) string {
+	var b strings.Builder
+	b.WriteString(string(check.Type))
+	keys := make([]string, 0, len(check.Options))
+	for key := range check.Options {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\x00%s=%s", key, check.Options[key])
+	}
+	return b.String()
+}
+
+// CheckFingerprints returns the fingerprint of every check of the
+// configuration, enabled or not, by CheckID
+func CheckFingerprints(cfg *Config) map[string]string {
+	fingerprints := make(map[string]string)
+	for _, host := range cfg.Hosts {
+		for i, check := range host.Checks {
+			fingerprints[CheckID(host.Name, i)] = CheckFingerprint(check)
+		}
+	}
+	return fingerprints
 }
 
 // Check represents a health check configuration
@@ -52,6 +110,19 @@ type Check struct {
 	Type             CheckType         `yaml:"type" toml:"type"`
 	Enabled          bool              `yaml:"enabled" toml:"enabled"`
 	Timeout          Duration          `yaml:"timeout" toml:"timeout"`
+	Warn             Duration          `yaml:"warn,omitempty" toml:"warn,omitempty"`
+	Critical         Duration          `yaml:"critical,omitempty" toml:"critical,omitempty"`
+	Interval         Duration          `yaml:"interval,omitempty" toml:"interval,omitempty"`
+	Schedule         string            `yaml:"schedule,omitempty" toml:"schedule,omitempty"`
+	FailingInterval  Duration          `yaml:"failing_interval,omitempty" toml:"failing_interval,omitempty"`
+	BackoffAfter     Duration          `yaml:"backoff_after,omitempty" toml:"backoff_after,omitempty"`
+	BackoffMax       Duration          `yaml:"backoff_max,omitempty" toml:"backoff_max,omitempty"`
+	FailAfter        int               `yaml:"fail_after,omitempty" toml:"fail_after,omitempty"`
+	RecoverAfter     int               `yaml:"recover_after,omitempty" toml:"recover_after,omitempty"`
+	Retries          int               `yaml:"retries,omitempty" toml:"retries,omitempty"`
+	RetryDelay       Duration          `yaml:"retry_delay,omitempty" toml:"retry_delay,omitempty"`
+	FlapThreshold    int               `yaml:"flap_threshold,omitempty" toml:"flap_threshold,omitempty"`
+	FlapWindow       Duration          `yaml:"flap_window,omitempty" toml:"flap_window,omitempty"`
 	HealthcheckIOURL string            `yaml:"healthcheck_io_url,omitempty" toml:"healthcheck_io_url,omitempty"`
 	Options          map[string]string `yaml:"options,omitempty" toml:"options,omitempty"`
 }
@@ -60,16 +131,75 @@ type Check struct {
 type CheckType string
 
 const (
-	CheckTypePing CheckType = "ping"
-	CheckTypeHTTP CheckType = "http"
+	CheckTypePing      CheckType = "ping"
+	CheckTypeHTTP      CheckType = "http"
+	CheckTypeTCP       CheckType = "tcp"
+	CheckTypeDNS       CheckType = "dns"
+	CheckTypeTLS       CheckType = "tls"
+	CheckTypeGRPC      CheckType = "grpc"
+	CheckTypeExec      CheckType = "exec"
+	CheckTypeHeartbeat CheckType = "heartbeat"
+	CheckTypeRedis     CheckType = "redis"
+	CheckTypePostgres  CheckType = "postgres"
+	CheckTypeMySQL     CheckType = "mysql"
 )
 
+// Level is the health reported by a single check result, following the
+// Nagios plugin levels
+type Level string
+
+const (
+	// LevelOK means the check passed
+	LevelOK Level = "OK"
+	// LevelWarning means the check passed but the service is degraded, e.g. slow or close to a limit
+	LevelWarning Level = "WARNING"
+	// LevelCritical means the check failed
+	LevelCritical Level = "CRITICAL"
+	// LevelUnknown means the check could not determine the health of the service, e.g. it could not run
+	LevelUnknown Level = "UNKNOWN"
+)
+
+// Failed reports whether the level counts as a failure. WARNING does not,
+// the service still works.
+func (l Level) Failed() bool {
+	return l != LevelOK && l != LevelWarning
+}
+
+// Worse returns the more severe of two levels
+func (l Level) Worse(other Level) Level {
+	if levelRank[other] > levelRank[l] {
+		return other
+	}
+	return l
+}
+
+// levelRank orders levels by severity
+var levelRank = map[Level]int{
+	LevelOK:       0,
+	LevelWarning:  1,
+	LevelUnknown:  2,
+	LevelCritical: 3,
+}
+
 // CheckResult represents the result of a health check
 type CheckResult struct {
 	Host      string
 	CheckType CheckType
-	Success   bool
+	Index     int // Position of the check within the host's checks
+	Level     Level
 	Message   string
 	Timestamp time.Time
 	Duration  time.Duration
+	Metrics   []Metric
+}
+
+// Metric is a named measurement reported by a check, such as Nagios performance data
+type Metric struct {
+	Label string
+	Value float64
+	Unit  string
+	Warn  string
+	Crit  string
+	Min   *float64
+	Max   *float64
 }
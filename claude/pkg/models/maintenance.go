@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// MaintenanceWindow is a planned period during which checks keep running but
+// no notifications are sent. A window is either one-off, from Start to End, or
+// recurs weekly on Days at At for Duration. It applies to every check of the
+// listed hosts ("*" for all hosts), of hosts with one of the listed tags, and
+// to the listed checks, written as "host/type".
+type MaintenanceWindow struct {
+	Name     string   `yaml:"name" toml:"name"`
+	Hosts    []string `yaml:"hosts,omitempty" toml:"hosts,omitempty"`
+	Tags     []string `yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Checks   []string `yaml:"checks,omitempty" toml:"checks,omitempty"`
+	Timezone string   `yaml:"timezone,omitempty" toml:"timezone,omitempty"`
+	Start    string   `yaml:"start,omitempty" toml:"start,omitempty"`
+	End      string   `yaml:"end,omitempty" toml:"end,omitempty"`
+	Days     []string `yaml:"days,omitempty" toml:"days,omitempty"`
+	At       string   `yaml:"at,omitempty" toml:"at,omitempty"`
+	Duration Duration `yaml:"duration,omitempty" toml:"duration,omitempty"`
+}
+
+// Silence is an ad-hoc maintenance period created from the web UI or API. It
+// applies to one check of a host, or to all checks of the host when Check is empty.
+type Silence struct {
+	ID      string    `yaml:"id" toml:"id"`
+	Host    string    `yaml:"host" toml:"host"`
+	Check   CheckType `yaml:"check,omitempty" toml:"check,omitempty"`
+	Reason  string    `yaml:"reason" toml:"reason"`
+	Created time.Time `yaml:"created" toml:"created"`
+	Expires time.Time `yaml:"expires" toml:"expires"`
+}
+
+// Active reports whether the silence has not expired at the given time
+func (s Silence) Active(now time.Time) bool {
+	return now.Before(s.Expires)
+}
@@ -0,0 +1,26 @@
+package models
+
+// ChannelType represents the type of a notification channel
+type ChannelType string
+
+const (
+	ChannelTypeWebhook      ChannelType = "webhook"
+	ChannelTypeHealthchecks ChannelType = "healthchecks_io"
+	ChannelTypeSlack        ChannelType = "slack"
+	ChannelTypeDiscord      ChannelType = "discord"
+	ChannelTypeTeams        ChannelType = "teams"
+	ChannelTypeEmail        ChannelType = "email"
+	ChannelTypePagerDuty    ChannelType = "pagerduty"
+	ChannelTypeOpsgenie     ChannelType = "opsgenie"
+	ChannelTypeNtfy         ChannelType = "ntfy"
+	ChannelTypeGotify       ChannelType = "gotify"
+	ChannelTypePushover     ChannelType = "pushover"
+)
+
+// Channel is a destination for notifications about checks that change state.
+// Hosts list the channels they notify by name.
+type Channel struct {
+	Name    string            `yaml:"name" toml:"name"`
+	Type    ChannelType       `yaml:"type" toml:"type"`
+	Options map[string]string `yaml:"options,omitempty" toml:"options,omitempty"`
+}
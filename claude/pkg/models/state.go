@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// State is the confirmed state of a check, derived from its recent results
+type State string
+
+const (
+	// StatePending means the check has not yet produced enough results to confirm a state
+	StatePending State = "PENDING"
+	// StateUp means the check is confirmed to be passing
+	StateUp State = "UP"
+	// StateDown means the check is confirmed to be failing
+	StateDown State = "DOWN"
+	// StateFlapping means the check changes between passing and failing too often to trust either
+	StateFlapping State = "FLAPPING"
+	// StateMaintenance means the check is in a maintenance window or silenced, so no notifications are sent
+	StateMaintenance State = "MAINTENANCE"
+	// StateUnreachable means the check is failing while a host or check it depends on is down, so no notifications are sent
+	StateUnreachable State = "UNREACHABLE"
+)
+
+// Status is the confirmed state of a check after applying its failure and
+// recovery thresholds and flap detection
+type Status struct {
+	State     State
+	Previous  State     // State before the latest result
+	Since     time.Time // When the check entered State
+	PrevSince time.Time // When the check entered Previous
+	Failures  int       // Consecutive failed results
+	Successes int       // Consecutive successful results
+	Reason    string    // Why the check is in MAINTENANCE or UNREACHABLE
+}
+
+// Changed reports whether the latest result changed the confirmed state
+func (s Status) Changed() bool {
+	return s.State != s.Previous
+}
+
+// RunMode is how the scheduler currently times the runs of a check
+type RunMode string
+
+const (
+	// RunModeNormal means the check runs on its configured interval or schedule
+	RunModeNormal RunMode = ""
+	// RunModeFast means the check is failing and runs at its failing_interval
+	RunModeFast RunMode = "fast"
+	// RunModeBackoff means the check has been failing for a long time and runs
+	// less and less often, up to its backoff_max
+	RunModeBackoff RunMode = "backoff"
+)
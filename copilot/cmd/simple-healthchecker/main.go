@@ -3,12 +3,14 @@ package main
 import (
 	"flag"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/notify"
 	"github.com/andrewsjg/simple-healthchecker/copilot/internal/server"
 	"github.com/andrewsjg/simple-healthchecker/copilot/internal/state"
 )
@@ -16,15 +18,25 @@ import (
 func main() {
 	cfgPath := flag.String("config", "config.yaml", "path to config file (yaml or toml)")
 	addr := flag.String("addr", ":8080", "http listen address")
-	interval := flag.Duration("interval", 30*time.Second, "check interval")
+	interval := flag.Duration("interval", 30*time.Second, "default check interval")
 	flag.Parse()
+	if *interval < config.MinInterval {
+		log.Fatalf("interval must be at least %v", config.MinInterval)
+	}
 
 	cfg, err := config.Load(*cfgPath)
 	if err != nil {
 		log.Fatalf("load config: %v", err)
 	}
 
+	notifiers := notify.Default()
+	if err := notifiers.Validate(cfg.Notifications); err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
 	st := state.New(cfg)
+	st.SetNotifiers(notifiers)
+	st.SetDashboardURL(dashboardURL(*addr))
 	st.SetConfigPath(*cfgPath)
 	stop := make(chan struct{})
 	st.StartScheduler(*interval, stop)
@@ -40,5 +52,18 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 	close(stop)
+	st.Wait()
 	_ = srv.Stop()
 }
+
+// dashboardURL is the local address of the web UI for a listen address.
+func dashboardURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
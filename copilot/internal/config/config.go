@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/knadh/koanf/parsers/toml"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -17,11 +18,26 @@ const (
 	CheckHTTP CheckType = "http"
 )
 
+const (
+	DefaultParallelism = 8
+	DefaultPingTimeout = 2 * time.Second
+	DefaultHTTPTimeout = 5 * time.Second
+)
+
 type Check struct {
 	Type    CheckType `koanf:"type" json:"type" yaml:"type" toml:"type"`
 	Enabled bool      `koanf:"enabled" json:"enabled" yaml:"enabled" toml:"enabled"`
 	URL     string    `koanf:"url" json:"url" yaml:"url" toml:"url"`
 	Expect  int       `koanf:"expect" json:"expect" yaml:"expect" toml:"expect"`
+	// Interval (e.g. 10s) or cron Schedule overrides the default check interval
+	Interval string `koanf:"interval" json:"interval,omitempty" yaml:"interval,omitempty" toml:"interval,omitempty"`
+	Schedule string `koanf:"schedule" json:"schedule,omitempty" yaml:"schedule,omitempty" toml:"schedule,omitempty"`
+	// Timeout (e.g. 3s) defaults to DefaultPingTimeout or DefaultHTTPTimeout
+	Timeout string `koanf:"timeout" json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	// Warn and Critical (e.g. 500ms, 2s) mark a passing check WARNING or
+	// CRITICAL when it takes longer
+	Warn     string `koanf:"warn" json:"warn,omitempty" yaml:"warn,omitempty" toml:"warn,omitempty"`
+	Critical string `koanf:"critical" json:"critical,omitempty" yaml:"critical,omitempty" toml:"critical,omitempty"`
 }
 
 type Host struct {
@@ -29,10 +45,186 @@ type Host struct {
 	Address             string  `koanf:"address" json:"address" yaml:"address" toml:"address"`
 	Checks              []Check `koanf:"checks" json:"checks" yaml:"checks" toml:"checks"`
 	HealthchecksPingURL string  `koanf:"healthchecks_ping_url" json:"healthchecks_ping_url" yaml:"healthchecks_ping_url" toml:"healthchecks_ping_url"`
+	// Notify names the notification channels told when a check of the host
+	// starts or stops failing
+	Notify []string `koanf:"notify" json:"notify,omitempty" yaml:"notify,omitempty" toml:"notify,omitempty"`
+}
+
+type ChannelType string
+
+const (
+	ChannelWebhook      ChannelType = "webhook"
+	ChannelHealthchecks ChannelType = "healthchecks"
+	ChannelSlack        ChannelType = "slack"
+	ChannelDiscord      ChannelType = "discord"
+	ChannelTeams        ChannelType = "teams"
+	ChannelEmail        ChannelType = "email"
+	ChannelPagerDuty    ChannelType = "pagerduty"
+	ChannelOpsgenie     ChannelType = "opsgenie"
+	ChannelNtfy         ChannelType = "ntfy"
+	ChannelGotify       ChannelType = "gotify"
+	ChannelPushover     ChannelType = "pushover"
+)
+
+// Channel is a destination for notifications. Which fields are used depends
+// on the channel type.
+type Channel struct {
+	Name string      `koanf:"name" json:"name" yaml:"name" toml:"name"`
+	Type ChannelType `koanf:"type" json:"type" yaml:"type" toml:"type"`
+	URL  string      `koanf:"url" json:"url" yaml:"url" toml:"url"`
+	// Method defaults to POST, Body is a Go template for the request body
+	// (defaults to the event as JSON)
+	Method  string            `koanf:"method" json:"method,omitempty" yaml:"method,omitempty" toml:"method,omitempty"`
+	Body    string            `koanf:"body" json:"body,omitempty" yaml:"body,omitempty" toml:"body,omitempty"`
+	Headers map[string]string `koanf:"headers" json:"headers,omitempty" yaml:"headers,omitempty" toml:"headers,omitempty"`
+	// Secret signs the body with HMAC-SHA256, sent in SignatureHeader
+	// (defaults to X-Healthchecker-Signature)
+	Secret          string `koanf:"secret" json:"secret,omitempty" yaml:"secret,omitempty" toml:"secret,omitempty"`
+	SignatureHeader string `koanf:"signature_header" json:"signature_header,omitempty" yaml:"signature_header,omitempty" toml:"signature_header,omitempty"`
+	// Retries (default 3) of failed deliveries, RetryDelay (default 1s) before
+	// the first retry, doubled for every further one, and Timeout (default 10s)
+	// for each attempt
+	Retries    *int   `koanf:"retries" json:"retries,omitempty" yaml:"retries,omitempty" toml:"retries,omitempty"`
+	RetryDelay string `koanf:"retry_delay" json:"retry_delay,omitempty" yaml:"retry_delay,omitempty" toml:"retry_delay,omitempty"`
+	Timeout    string `koanf:"timeout" json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	// Email channels send through the SMTP server at Host:Port (default 587,
+	// or 465 with implicit TLS). Security is starttls (default), tls or none.
+	Host          string   `koanf:"host" json:"host,omitempty" yaml:"host,omitempty" toml:"host,omitempty"`
+	Port          int      `koanf:"port" json:"port,omitempty" yaml:"port,omitempty" toml:"port,omitempty"`
+	Security      string   `koanf:"security" json:"security,omitempty" yaml:"security,omitempty" toml:"security,omitempty"`
+	Username      string   `koanf:"username" json:"username,omitempty" yaml:"username,omitempty" toml:"username,omitempty"`
+	Password      string   `koanf:"password" json:"password,omitempty" yaml:"password,omitempty" toml:"password,omitempty"`
+	From          string   `koanf:"from" json:"from,omitempty" yaml:"from,omitempty" toml:"from,omitempty"`
+	To            []string `koanf:"to" json:"to,omitempty" yaml:"to,omitempty" toml:"to,omitempty"`
+	TLSSkipVerify bool     `koanf:"tls_skip_verify" json:"tls_skip_verify,omitempty" yaml:"tls_skip_verify,omitempty" toml:"tls_skip_verify,omitempty"`
+	// Digest (e.g. 5m) batches all emails within the window into one
+	Digest string `koanf:"digest" json:"digest,omitempty" yaml:"digest,omitempty" toml:"digest,omitempty"`
+	// PagerDuty channels need the RoutingKey of an Events API v2 integration,
+	// Opsgenie channels the APIKey of an API integration. Priority overrides
+	// the PagerDuty severity, Opsgenie priority or push priority of CRITICAL
+	// and UNKNOWN, and Responders are Opsgenie teams the alert is routed to.
+	RoutingKey string            `koanf:"routing_key" json:"routing_key,omitempty" yaml:"routing_key,omitempty" toml:"routing_key,omitempty"`
+	APIKey     string            `koanf:"api_key" json:"api_key,omitempty" yaml:"api_key,omitempty" toml:"api_key,omitempty"`
+	Priority   map[string]string `koanf:"priority" json:"priority,omitempty" yaml:"priority,omitempty" toml:"priority,omitempty"`
+	Responders []string          `koanf:"responders" json:"responders,omitempty" yaml:"responders,omitempty" toml:"responders,omitempty"`
+	// Push channels: Token is the ntfy access token (or use Username and
+	// Password), Gotify app token or Pushover API token. Tags are ntfy tags,
+	// UserKey the Pushover user or group. Pushover emergency priority (2)
+	// repeats every EmergencyRetry (default 1m) for EmergencyExpire (default 1h).
+	Token           string   `koanf:"token" json:"token,omitempty" yaml:"token,omitempty" toml:"token,omitempty"`
+	Tags            []string `koanf:"tags" json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	UserKey         string   `koanf:"user_key" json:"user_key,omitempty" yaml:"user_key,omitempty" toml:"user_key,omitempty"`
+	EmergencyRetry  string   `koanf:"emergency_retry" json:"emergency_retry,omitempty" yaml:"emergency_retry,omitempty" toml:"emergency_retry,omitempty"`
+	EmergencyExpire string   `koanf:"emergency_expire" json:"emergency_expire,omitempty" yaml:"emergency_expire,omitempty" toml:"emergency_expire,omitempty"`
 }
 
 type Config struct {
-	Hosts []Host `koanf:"hosts" json:"hosts" yaml:"hosts" toml:"hosts"`
+	// Parallelism limits how many checks run at once (default DefaultParallelism)
+	Parallelism int `koanf:"parallelism" json:"parallelism,omitempty" yaml:"parallelism,omitempty" toml:"parallelism,omitempty"`
+	// TargetConcurrency limits how many checks run at once against the same
+	// address or domain, and TargetInterval (e.g. 200ms) how soon after each
+	// other they may start (default no limit)
+	TargetConcurrency int    `koanf:"target_concurrency" json:"target_concurrency,omitempty" yaml:"target_concurrency,omitempty" toml:"target_concurrency,omitempty"`
+	TargetInterval    string `koanf:"target_interval" json:"target_interval,omitempty" yaml:"target_interval,omitempty" toml:"target_interval,omitempty"`
+	Hosts             []Host `koanf:"hosts" json:"hosts" yaml:"hosts" toml:"hosts"`
+	// Notifications are the channels hosts can notify
+	Notifications []Channel `koanf:"notifications" json:"notifications,omitempty" yaml:"notifications,omitempty" toml:"notifications,omitempty"`
+	// DashboardURL is linked from chat notifications (default
+	// http://localhost plus the -addr port)
+	DashboardURL string `koanf:"dashboard_url" json:"dashboard_url,omitempty" yaml:"dashboard_url,omitempty" toml:"dashboard_url,omitempty"`
+}
+
+// CheckTimeout returns the timeout for a check, falling back to the default
+// for its type when none is set.
+func CheckTimeout(c Check) (time.Duration, error) {
+	if c.Timeout == "" {
+		if c.Type == CheckHTTP {
+			return DefaultHTTPTimeout, nil
+		}
+		return DefaultPingTimeout, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("bad timeout %q: %w", c.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return d, nil
+}
+
+// TargetLimits returns the per-target concurrency and interval, zero when
+// not limited.
+func TargetLimits(cfg *Config) (int, time.Duration, error) {
+	if cfg.TargetConcurrency < 0 {
+		return 0, 0, fmt.Errorf("target_concurrency must not be negative")
+	}
+	if cfg.TargetInterval == "" {
+		return cfg.TargetConcurrency, 0, nil
+	}
+	d, err := time.ParseDuration(cfg.TargetInterval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad target_interval %q: %w", cfg.TargetInterval, err)
+	}
+	if d < 0 {
+		return 0, 0, fmt.Errorf("target_interval must not be negative")
+	}
+	return cfg.TargetConcurrency, d, nil
+}
+
+// LatencyThresholds returns the warn and critical latency of a check, zero
+// when not set.
+func LatencyThresholds(c Check) (warn, critical time.Duration, err error) {
+	parse := func(name, v string) (time.Duration, error) {
+		if v == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("bad %s %q: %w", name, v, err)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("%s must not be negative", name)
+		}
+		return d, nil
+	}
+	if warn, err = parse("warn", c.Warn); err != nil {
+		return 0, 0, err
+	}
+	if critical, err = parse("critical", c.Critical); err != nil {
+		return 0, 0, err
+	}
+	if warn > 0 && critical > 0 && warn >= critical {
+		return 0, 0, fmt.Errorf("warn must be less than critical")
+	}
+	return warn, critical, nil
+}
+
+// ValidateNotifications checks that channels have unique names and a type
+// and that hosts only notify channels that exist. Channel settings are
+// checked by the notify package.
+func ValidateNotifications(cfg *Config) error {
+	names := make(map[string]bool, len(cfg.Notifications))
+	for i, ch := range cfg.Notifications {
+		if ch.Name == "" {
+			return fmt.Errorf("notification channel %d has no name", i)
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("notification channel %s is defined more than once", ch.Name)
+		}
+		if ch.Type == "" {
+			return fmt.Errorf("notification channel %s has no type", ch.Name)
+		}
+		names[ch.Name] = true
+	}
+	for _, h := range cfg.Hosts {
+		for _, name := range h.Notify {
+			if !names[name] {
+				return fmt.Errorf("host %s: unknown notification channel %q", h.Name, name)
+			}
+		}
+	}
+	return nil
 }
 
 func Load(path string) (*Config, error) {
@@ -54,11 +246,31 @@ func Load(path string) (*Config, error) {
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, err
 	}
+	if cfg.Parallelism < 0 {
+		return nil, fmt.Errorf("parallelism must not be negative")
+	}
+	if _, _, err := TargetLimits(&cfg); err != nil {
+		return nil, err
+	}
+	if err := ValidateNotifications(&cfg); err != nil {
+		return nil, err
+	}
 	// ensure at least one ping check if none provided
 	for i := range cfg.Hosts {
 		if len(cfg.Hosts[i].Checks) == 0 {
 			cfg.Hosts[i].Checks = []Check{{Type: CheckPing, Enabled: true}}
 		}
+		for j, c := range cfg.Hosts[i].Checks {
+			if _, err := ParseSchedule(c, MinInterval); err != nil {
+				return nil, fmt.Errorf("host %s check %d: %w", cfg.Hosts[i].Name, j, err)
+			}
+			if _, err := CheckTimeout(c); err != nil {
+				return nil, fmt.Errorf("host %s check %d: %w", cfg.Hosts[i].Name, j, err)
+			}
+			if _, _, err := LatencyThresholds(c); err != nil {
+				return nil, fmt.Errorf("host %s check %d: %w", cfg.Hosts[i].Name, j, err)
+			}
+		}
 	}
 	return &cfg, nil
 }
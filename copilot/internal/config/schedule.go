@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MinInterval is the shortest interval a check may run at.
+const MinInterval = time.Second
+
+// Schedule returns the next time a check is due after t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+// five cron fields with optional leading seconds, or descriptors like @hourly
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseSchedule returns the schedule for a check, falling back to def when
+// neither interval nor schedule is set.
+func ParseSchedule(c Check, def time.Duration) (Schedule, error) {
+	if c.Schedule != "" {
+		if c.Interval != "" {
+			return nil, fmt.Errorf("interval and schedule are mutually exclusive")
+		}
+		sched, err := cronParser.Parse(c.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("bad schedule %q: %w", c.Schedule, err)
+		}
+		// robfig returns the zero time for expressions matching no date, like 0 0 30 2 *
+		if sched.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("bad schedule %q: never fires", c.Schedule)
+		}
+		return sched, nil
+	}
+	d := def
+	if c.Interval != "" {
+		var err error
+		if d, err = time.ParseDuration(c.Interval); err != nil {
+			return nil, fmt.Errorf("bad interval %q: %w", c.Interval, err)
+		}
+	}
+	if d < MinInterval {
+		return nil, fmt.Errorf("interval %v is below minimum %v", d, MinInterval)
+	}
+	return every(d), nil
+}
+
+// IsInterval reports whether a schedule runs at a fixed interval rather than
+// at cron times.
+func IsInterval(s Schedule) bool {
+	_, ok := s.(every)
+	return ok
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2025, 3, 2, 10, 20, 30, 0, time.UTC)
+	cases := []struct {
+		name     string
+		check    Check
+		next     time.Time
+		interval bool
+		err      bool
+	}{
+		{"default", Check{}, from.Add(time.Minute), true, false},
+		{"interval", Check{Interval: "10s"}, from.Add(10 * time.Second), true, false},
+		{"cron", Check{Schedule: "0 * * * *"}, time.Date(2025, 3, 2, 11, 0, 0, 0, time.UTC), false, false},
+		{"cron seconds", Check{Schedule: "*/15 * * * * *"}, time.Date(2025, 3, 2, 10, 20, 45, 0, time.UTC), false, false},
+		{"descriptor", Check{Schedule: "@daily"}, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), false, false},
+		{"both", Check{Interval: "1m", Schedule: "@hourly"}, time.Time{}, false, true},
+		{"bad cron", Check{Schedule: "every day"}, time.Time{}, false, true},
+		{"never fires", Check{Schedule: "0 0 30 2 *"}, time.Time{}, false, true},
+		{"bad interval", Check{Interval: "often"}, time.Time{}, false, true},
+		{"below minimum", Check{Interval: "500ms"}, time.Time{}, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := ParseSchedule(c.check, time.Minute)
+			if (err != nil) != c.err {
+				t.Fatalf("err = %v, want error %v", err, c.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := s.Next(from); !got.Equal(c.next) {
+				t.Errorf("next = %v, want %v", got, c.next)
+			}
+			if IsInterval(s) != c.interval {
+				t.Errorf("IsInterval = %v, want %v", IsInterval(s), c.interval)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// Chat posts events to a chat incoming-webhook URL, rendered by format into
+// the service's rich message JSON.
+type Chat struct {
+	typ    config.ChannelType
+	format func(ev Event) any
+	client *http.Client
+}
+
+func NewChat(typ config.ChannelType, format func(ev Event) any) *Chat {
+	return &Chat{typ: typ, format: format, client: &http.Client{}}
+}
+
+func (c *Chat) Type() config.ChannelType { return c.typ }
+
+func (c *Chat) Validate(ch config.Channel) error {
+	if u, err := url.Parse(ch.URL); ch.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("bad url %q", ch.URL)
+	}
+	_, err := parsePolicy(ch)
+	return err
+}
+
+func (c *Chat) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := c.Validate(ch); err != nil {
+		return err
+	}
+	p, _ := parsePolicy(ch)
+	return postJSON(ctx, c.client, p, ch.URL, nil, c.format(ev))
+}
+
+// title summarises an event in one line, e.g. "🔴 router ping check is CRITICAL".
+func title(ev Event) string {
+	check := fmt.Sprintf("%s %s check", ev.Host, ev.Check)
+	switch {
+	case !ev.Failed && ev.Outage > 0:
+		return fmt.Sprintf("✅ %s recovered after %s", check, roundOutage(ev.Outage))
+	case !ev.Failed:
+		return fmt.Sprintf("✅ %s is %s", check, ev.Level)
+	}
+	return fmt.Sprintf("🔴 %s is %s", check, ev.Level)
+}
+
+// fact is a labelled value shown in a chat message.
+type fact struct{ name, value string }
+
+func facts(ev Event) []fact {
+	prev := ev.Previous
+	if prev == "" {
+		prev = "PENDING"
+	}
+	host := ev.Host
+	if ev.Address != "" && ev.Address != ev.Host {
+		host += " (" + ev.Address + ")"
+	}
+	fs := []fact{
+		{"Host", host},
+		{"Check", string(ev.Check)},
+		{"Level", prev + " → " + ev.Level},
+		{"Latency", fmt.Sprintf("%dms", ev.LatencyMS)},
+	}
+	if ev.Outage > 0 {
+		fs = append(fs, fact{"Outage", roundOutage(ev.Outage)})
+	}
+	return fs
+}
+
+func roundOutage(d time.Duration) string {
+	if d >= time.Hour {
+		return d.Round(time.Minute).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// color is the RGB color of an event: red while failing, green otherwise.
+func color(ev Event) int {
+	if ev.Failed {
+		return 0xE01E5A
+	}
+	return 0x2EB67D
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// slackMessage renders an event as Slack blocks.
+func slackMessage(ev Event) any {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type block struct {
+		Type     string `json:"type"`
+		Text     *text  `json:"text,omitempty"`
+		Fields   []text `json:"fields,omitempty"`
+		Elements []text `json:"elements,omitempty"`
+	}
+	var fields []text
+	for _, f := range facts(ev) {
+		fields = append(fields, text{"mrkdwn", fmt.Sprintf("*%s*\n%s", f.name, slackEscape(f.value))})
+	}
+	blocks := []block{
+		{Type: "header", Text: &text{"plain_text", truncate(title(ev), 150)}},
+		{Type: "section", Fields: fields},
+	}
+	if ev.Message != "" {
+		blocks = append(blocks, block{Type: "section", Text: &text{"mrkdwn", truncate("*Message*\n"+slackEscape(ev.Message), 3000)}})
+	}
+	if ev.DashboardURL != "" {
+		blocks = append(blocks, block{Type: "context", Elements: []text{{"mrkdwn", "<" + ev.DashboardURL + "|Open dashboard>"}}})
+	}
+	return map[string]any{"text": title(ev), "blocks": blocks}
+}
+
+// discordMessage renders an event as a Discord embed.
+func discordMessage(ev Event) any {
+	type field struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline"`
+	}
+	var fields []field
+	for _, f := range facts(ev) {
+		fields = append(fields, field{f.name, truncate(f.value, 1024), true})
+	}
+	embed := map[string]any{
+		"title":     truncate(title(ev), 256),
+		"color":     color(ev),
+		"fields":    fields,
+		"timestamp": ev.CheckedAt.UTC().Format(time.RFC3339),
+	}
+	if ev.Message != "" {
+		embed["description"] = truncate(ev.Message, 4096)
+	}
+	if ev.DashboardURL != "" {
+		embed["url"] = ev.DashboardURL
+	}
+	return map[string]any{"embeds": []any{embed}}
+}
+
+// teamsMessage renders an event as a Teams Adaptive Card.
+func teamsMessage(ev Event) any {
+	var fs []map[string]string
+	for _, f := range facts(ev) {
+		fs = append(fs, map[string]string{"title": f.name, "value": f.value})
+	}
+	textColor := "Good"
+	if ev.Failed {
+		textColor = "Attention"
+	}
+	body := []map[string]any{
+		{"type": "TextBlock", "text": title(ev), "weight": "Bolder", "size": "Medium", "color": textColor, "wrap": true},
+		{"type": "FactSet", "facts": fs},
+	}
+	if ev.Message != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": ev.Message, "wrap": true})
+	}
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if ev.DashboardURL != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "Open dashboard", "url": ev.DashboardURL}}
+	}
+	return map[string]any{
+		"type":        "message",
+		"attachments": []any{map[string]any{"contentType": "application/vnd.microsoft.card.adaptive", "content": card}},
+	}
+}
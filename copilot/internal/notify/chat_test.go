@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// postChat sends ev through the chat notifier of typ and decodes the payload.
+func postChat(t *testing.T, typ config.ChannelType, ev Event) map[string]any {
+	t.Helper()
+	srv, requests := recorder(t)
+	n, err := Default().Get(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), config.Channel{Type: typ, URL: srv.URL}, ev); err != nil {
+		t.Fatal(err)
+	}
+	got := requests()
+	if len(got) != 1 || got[0].method != "POST" || got[0].header.Get("Content-Type") != "application/json" {
+		t.Fatalf("requests = %+v, want one JSON POST", got)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("payload %s: %v", got[0].body, err)
+	}
+	return payload
+}
+
+// at walks a decoded JSON value by object keys and array indexes.
+func at(t *testing.T, v any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch k := p.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				t.Fatalf("%v: not an object at %q", path, k)
+			}
+			v = m[k]
+		case int:
+			a, ok := v.([]any)
+			if !ok || k >= len(a) {
+				t.Fatalf("%v: no element %d", path, k)
+			}
+			v = a[k]
+		}
+	}
+	return v
+}
+
+func TestSlackMessage(t *testing.T) {
+	ev := downEvent()
+	ev.Message = "status 503 <html> & more"
+	p := postChat(t, config.ChannelSlack, ev)
+
+	if got := p["text"]; got != "🔴 web-1 http check is CRITICAL" {
+		t.Errorf("text = %q", got)
+	}
+	if len(at(t, p, "blocks").([]any)) != 4 {
+		t.Fatalf("blocks = %v, want header, fields, message and context", p["blocks"])
+	}
+	if at(t, p, "blocks", 0, "type") != "header" || at(t, p, "blocks", 0, "text", "type") != "plain_text" {
+		t.Errorf("first block = %v, want a plain_text header", at(t, p, "blocks", 0))
+	}
+	want := []string{"*Host*\nweb-1 (10.0.0.5)", "*Check*\nhttp", "*Level*\nOK → CRITICAL", "*Latency*\n1500ms"}
+	var fields []string
+	for _, f := range at(t, p, "blocks", 1, "fields").([]any) {
+		fields = append(fields, at(t, f, "text").(string))
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %q, want %q", fields, want)
+	}
+	if got := at(t, p, "blocks", 2, "text", "text"); got != "*Message*\nstatus 503 &lt;html&gt; &amp; more" {
+		t.Errorf("message = %q, want it escaped", got)
+	}
+	if got := at(t, p, "blocks", 3, "elements", 0, "text"); got != "<https://status.example.com|Open dashboard>" {
+		t.Errorf("context = %q", got)
+	}
+
+	rec := postChat(t, config.ChannelSlack, upEvent())
+	if got := rec["text"]; got != "✅ web-1 http check recovered after 5m30s" {
+		t.Errorf("recovery text = %q", got)
+	}
+	if got := at(t, rec, "blocks", 1, "fields", 4, "text"); got != "*Outage*\n5m30s" {
+		t.Errorf("outage field = %q", got)
+	}
+}
+
+func TestDiscordMessage(t *testing.T) {
+	p := postChat(t, config.ChannelDiscord, downEvent())
+	embed := at(t, p, "embeds", 0)
+	if got := at(t, embed, "title"); got != "🔴 web-1 http check is CRITICAL" {
+		t.Errorf("title = %q", got)
+	}
+	if got := at(t, embed, "color"); got != float64(0xE01E5A) {
+		t.Errorf("color = %v, want red", got)
+	}
+	if at(t, embed, "description") != "status 503 (expect 200)" || at(t, embed, "url") != "https://status.example.com" ||
+		at(t, embed, "timestamp") != "2025-03-02T10:00:00Z" {
+		t.Errorf("embed = %v", embed)
+	}
+	if f := at(t, embed, "fields", 2); at(t, f, "name") != "Level" || at(t, f, "value") != "OK → CRITICAL" || at(t, f, "inline") != true {
+		t.Errorf("level field = %v", f)
+	}
+
+	rec := at(t, postChat(t, config.ChannelDiscord, upEvent()), "embeds", 0)
+	if got := at(t, rec, "color"); got != float64(0x2EB67D) {
+		t.Errorf("recovery color = %v, want green", got)
+	}
+}
+
+func TestTeamsMessage(t *testing.T) {
+	p := postChat(t, config.ChannelTeams, downEvent())
+	if p["type"] != "message" || at(t, p, "attachments", 0, "contentType") != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("payload = %v, want a message with an adaptive card", p)
+	}
+	card := at(t, p, "attachments", 0, "content")
+	if at(t, card, "type") != "AdaptiveCard" || at(t, card, "version") != "1.4" {
+		t.Errorf("card = %v", card)
+	}
+	if at(t, card, "body", 0, "text") != "🔴 web-1 http check is CRITICAL" || at(t, card, "body", 0, "color") != "Attention" {
+		t.Errorf("title block = %v", at(t, card, "body", 0))
+	}
+	if f := at(t, card, "body", 1, "facts", 0); at(t, f, "title") != "Host" || at(t, f, "value") != "web-1 (10.0.0.5)" {
+		t.Errorf("host fact = %v", f)
+	}
+	if at(t, card, "body", 2, "text") != "status 503 (expect 200)" {
+		t.Errorf("message block = %v", at(t, card, "body", 2))
+	}
+	if a := at(t, card, "actions", 0); at(t, a, "type") != "Action.OpenUrl" || at(t, a, "url") != "https://status.example.com" {
+		t.Errorf("action = %v", a)
+	}
+
+	rec := at(t, postChat(t, config.ChannelTeams, upEvent()), "attachments", 0, "content")
+	if at(t, rec, "body", 0, "color") != "Good" {
+		t.Errorf("recovery title block = %v", at(t, rec, "body", 0))
+	}
+}
@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+var (
+	emailFuncs = map[string]any{"outage": roundOutage}
+	emailText  = template.Must(template.New("").Funcs(emailFuncs).ParseFS(templatesFS, "templates/*.txt"))
+	emailHTML  = htmltemplate.Must(htmltemplate.New("").Funcs(emailFuncs).ParseFS(templatesFS, "templates/*.html"))
+)
+
+// Email sends events over SMTP as HTML emails with a plain-text part. With a
+// digest window, events are batched per channel into a single email.
+type Email struct {
+	mu      sync.Mutex
+	batches map[string]*batch // pending digests by channel name
+	flushed bool              // after Flush, emails are sent without waiting
+}
+
+// batch is a digest waiting for its window to end.
+type batch struct {
+	ch     config.Channel
+	events []Event
+	timer  *time.Timer
+	sent   chan struct{} // closed once the digest is sent
+	err    error
+}
+
+func NewEmail() *Email {
+	return &Email{batches: make(map[string]*batch)}
+}
+
+func (e *Email) Type() config.ChannelType { return config.ChannelEmail }
+
+func (e *Email) Validate(ch config.Channel) error {
+	if ch.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	switch ch.Security {
+	case "", "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("bad security %q (starttls, tls or none)", ch.Security)
+	}
+	if ch.Port < 0 || ch.Port > 65535 {
+		return fmt.Errorf("bad port %d", ch.Port)
+	}
+	if _, err := mail.ParseAddress(ch.From); err != nil {
+		return fmt.Errorf("bad from %q: %w", ch.From, err)
+	}
+	if len(ch.To) == 0 {
+		return fmt.Errorf("to is required")
+	}
+	for _, to := range ch.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("bad to %q: %w", to, err)
+		}
+	}
+	if _, err := digestWindow(ch); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Notify emails an event, or adds it to the channel's pending digest and
+// waits until the digest is sent.
+func (e *Email) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := e.Validate(ch); err != nil {
+		return err
+	}
+	window, _ := digestWindow(ch)
+
+	e.mu.Lock()
+	if window == 0 || e.flushed {
+		e.mu.Unlock()
+		return sendEmail(ch, []Event{ev})
+	}
+	b, ok := e.batches[ch.Name]
+	if !ok {
+		b = &batch{sent: make(chan struct{})}
+		b.timer = time.AfterFunc(window, func() { e.flush(ch.Name, b) })
+		e.batches[ch.Name] = b
+	}
+	b.ch = ch
+	b.events = append(b.events, ev)
+	e.mu.Unlock()
+
+	select {
+	case <-b.sent:
+		return b.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush sends pending digests now and later emails without waiting, so
+// nothing is lost on shutdown.
+func (e *Email) Flush() {
+	e.mu.Lock()
+	e.flushed = true
+	pending := make(map[string]*batch, len(e.batches))
+	for name, b := range e.batches {
+		pending[name] = b
+	}
+	e.mu.Unlock()
+	for name, b := range pending {
+		b.timer.Stop()
+		e.flush(name, b)
+	}
+}
+
+func (e *Email) flush(name string, b *batch) {
+	e.mu.Lock()
+	if e.batches[name] != b {
+		e.mu.Unlock()
+		return // already sent
+	}
+	delete(e.batches, name)
+	e.mu.Unlock()
+	b.err = sendEmail(b.ch, b.events)
+	close(b.sent)
+}
+
+func digestWindow(ch config.Channel) (time.Duration, error) {
+	if ch.Digest == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(ch.Digest)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("bad digest %q", ch.Digest)
+	}
+	return d, nil
+}
+
+// sendEmail renders and sends one email: a down or recovery email for a
+// single event, a digest for several.
+func sendEmail(ch config.Channel, events []Event) error {
+	name, data := "digest", any(events)
+	subject := fmt.Sprintf("[simple-healthchecker] %d checks changed level", len(events))
+	if len(events) == 1 {
+		ev := events[0]
+		data = ev
+		if ev.Failed {
+			name = "down"
+			subject = fmt.Sprintf("[simple-healthchecker] %s: %s %s check", ev.Level, ev.Host, ev.Check)
+		} else {
+			name = "recovery"
+			subject = fmt.Sprintf("[simple-healthchecker] RECOVERED: %s %s check", ev.Host, ev.Check)
+			if ev.Outage > 0 {
+				subject += " after " + roundOutage(ev.Outage)
+			}
+		}
+	}
+
+	var text, html bytes.Buffer
+	if err := emailText.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return err
+	}
+	if err := emailHTML.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		typ     string
+		content []byte
+	}{{"text/plain; charset=utf-8", text.Bytes()}, {"text/html; charset=utf-8", html.Bytes()}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.typ}, "Content-Transfer-Encoding": {"quoted-printable"}})
+		if err != nil {
+			return err
+		}
+		qp := quotedprintable.NewWriter(w)
+		qp.Write(part.content)
+		qp.Close()
+	}
+	mw.Close()
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\n",
+		ch.From, strings.Join(ch.To, ", "), mime.QEncoding.Encode("utf-8", subject), time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+
+	return deliver(ch, msg.Bytes())
+}
+
+// deliver sends a message through the channel's SMTP server.
+func deliver(ch config.Channel, msg []byte) error {
+	port := ch.Port
+	if port == 0 {
+		port = 587
+		if ch.Security == "tls" {
+			port = 465
+		}
+	}
+	timeout := DefaultTimeout
+	if d, err := time.ParseDuration(ch.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	addr := net.JoinHostPort(ch.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: ch.Host, InsecureSkipVerify: ch.TLSSkipVerify}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if ch.Security == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	c, err := smtp.NewClient(conn, ch.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ch.Security == "" || ch.Security == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", addr)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if ch.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", ch.Username, ch.Password, ch.Host)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(ch.From)
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range ch.To {
+		addr, _ := mail.ParseAddress(to)
+		if err := c.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,226 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// email is a message received by the SMTP stand-in.
+type email struct {
+	from    string
+	to      []string
+	subject string
+	text    string // the plain-text part
+}
+
+// smtpServer starts a plain SMTP server that accepts every message, and
+// returns a channel that sends to it.
+func smtpServer(t *testing.T) (config.Channel, func() []email) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var mu sync.Mutex
+	var got []email
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				tp := textproto.NewConn(conn)
+				var m email
+				tp.PrintfLine("220 localhost ESMTP")
+				for {
+					line, err := tp.ReadLine()
+					if err != nil {
+						return
+					}
+					cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+					switch {
+					case cmd == "EHLO" || cmd == "HELO" || cmd == "RSET" || cmd == "NOOP":
+						tp.PrintfLine("250 localhost")
+					case cmd == "MAIL":
+						m.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+						tp.PrintfLine("250 ok")
+					case cmd == "RCPT":
+						m.to = append(m.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+						tp.PrintfLine("250 ok")
+					case cmd == "DATA":
+						tp.PrintfLine("354 go ahead")
+						data, err := tp.ReadDotBytes()
+						if err != nil {
+							return
+						}
+						if err := parseEmail(&m, data); err != nil {
+							t.Errorf("parse email: %v", err)
+						}
+						mu.Lock()
+						got = append(got, m)
+						mu.Unlock()
+						m = email{}
+						tp.PrintfLine("250 queued")
+					case cmd == "QUIT":
+						tp.PrintfLine("221 bye")
+						return
+					default:
+						tp.PrintfLine("502 not implemented")
+					}
+				}
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	ch := config.Channel{
+		Name: "ops-mail", Type: config.ChannelEmail, Host: host, Port: p, Security: "none",
+		From: "Healthchecker <hc@example.com>", To: []string{"ops@example.com", "oncall@example.com"},
+	}
+	return ch, func() []email {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]email(nil), got...)
+	}
+}
+
+func parseEmail(m *email, data []byte) error {
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+	if m.subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		return err
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	part, err := multipart.NewReader(msg.Body, params["boundary"]).NextPart()
+	if err != nil {
+		return err
+	}
+	text, err := io.ReadAll(quotedprintable.NewReader(part))
+	m.text = string(text)
+	return err
+}
+
+func TestEmailSend(t *testing.T) {
+	ch, received := smtpServer(t)
+	e := NewEmail()
+	if err := e.Notify(context.Background(), ch, downEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Notify(context.Background(), ch, upEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := received()
+	if len(got) != 2 {
+		t.Fatalf("received %d emails, want 2", len(got))
+	}
+	if m := got[0]; m.from != "hc@example.com" || strings.Join(m.to, ",") != "ops@example.com,oncall@example.com" {
+		t.Errorf("envelope from %q to %q", m.from, m.to)
+	}
+	if m := got[0]; m.subject != "[simple-healthchecker] CRITICAL: web-1 http check" ||
+		!strings.Contains(m.text, "web-1 http check is CRITICAL") || !strings.Contains(m.text, "Level:   OK -> CRITICAL") {
+		t.Errorf("down email %q:\n%s", m.subject, m.text)
+	}
+	if m := got[1]; m.subject != "[simple-healthchecker] RECOVERED: web-1 http check after 5m30s" {
+		t.Errorf("recovery subject = %q", m.subject)
+	}
+}
+
+func TestEmailDigest(t *testing.T) {
+	ch, received := smtpServer(t)
+	ch.Digest = "100ms"
+	e := NewEmail()
+
+	db := downEvent()
+	db.Host, db.Check, db.Message = "db-1", config.CheckPing, "100% packet loss"
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, ev := range []Event{downEvent(), db} {
+		wg.Add(1)
+		go func(ev Event) {
+			defer wg.Done()
+			errs <- e.Notify(context.Background(), ch, ev)
+		}(ev)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := received()
+	if len(got) != 1 {
+		t.Fatalf("received %d emails, want one digest", len(got))
+	}
+	if m := got[0]; m.subject != "[simple-healthchecker] 2 checks changed level" ||
+		!strings.Contains(m.text, "web-1 http check OK -> CRITICAL") || !strings.Contains(m.text, "db-1 ping check OK -> CRITICAL") {
+		t.Errorf("digest %q:\n%s", m.subject, m.text)
+	}
+}
+
+func TestEmailFlush(t *testing.T) {
+	ch, received := smtpServer(t)
+	ch.Digest = "1h"
+	e := NewEmail()
+
+	sent := make(chan error, 1)
+	go func() { sent <- e.Notify(context.Background(), ch, downEvent()) }()
+	for deadline := time.Now().Add(time.Second); ; {
+		e.mu.Lock()
+		pending := len(e.batches)
+		e.mu.Unlock()
+		if pending == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event was not added to a digest")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	e.Flush()
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Notify did not return after Flush")
+	}
+	if got := received(); len(got) != 1 || !strings.Contains(got[0].subject, "CRITICAL: web-1 http check") {
+		t.Fatalf("received %+v, want the pending event sent on Flush", got)
+	}
+
+	// after Flush, emails are sent without waiting for the digest window
+	if err := e.Notify(context.Background(), ch, upEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if got := received(); len(got) != 2 {
+		t.Errorf("received %d emails, want 2", len(got))
+	}
+}
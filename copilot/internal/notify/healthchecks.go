@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// Healthchecks pings a Healthchecks.io URL: <url> on success, <url>/fail on
+// failure. It is sent every result, as Healthchecks.io alerts on missing pings.
+type Healthchecks struct {
+	client *http.Client
+}
+
+func NewHealthchecks() *Healthchecks {
+	return &Healthchecks{client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (h *Healthchecks) Type() config.ChannelType { return config.ChannelHealthchecks }
+
+func (h *Healthchecks) Validate(ch config.Channel) error {
+	if ch.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	return nil
+}
+
+func (h *Healthchecks) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	url := ch.URL
+	if ev.Failed {
+		url = strings.TrimSuffix(url, "/") + "/fail"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
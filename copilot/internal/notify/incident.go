@@ -0,0 +1,246 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// Default endpoints of the incident services. Opsgenie accounts in the EU
+// region use https://api.eu.opsgenie.com as url.
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com"
+)
+
+// PagerDuty opens an incident through the Events API v2 when a check starts
+// failing and resolves it when the check recovers. The dedup key is
+// "host/check", so one check has one incident however often it fails.
+type PagerDuty struct {
+	client *http.Client
+}
+
+// Opsgenie creates an alert when a check starts failing and closes it when
+// the check recovers. Like PagerDuty, the alias is "host/check".
+type Opsgenie struct {
+	client *http.Client
+}
+
+func NewPagerDuty() *PagerDuty { return &PagerDuty{client: &http.Client{}} }
+func NewOpsgenie() *Opsgenie   { return &Opsgenie{client: &http.Client{}} }
+
+func (p *PagerDuty) Type() config.ChannelType { return config.ChannelPagerDuty }
+func (o *Opsgenie) Type() config.ChannelType  { return config.ChannelOpsgenie }
+
+// Default severities and priorities of failing levels, overridden by the
+// channel's priority map.
+var (
+	pagerDutySeverity = map[string]string{"CRITICAL": "critical", "UNKNOWN": "error"}
+	opsgeniePriority  = map[string]string{"CRITICAL": "P1", "UNKNOWN": "P2"}
+)
+
+func (p *PagerDuty) Validate(ch config.Channel) error {
+	if ch.RoutingKey == "" {
+		return fmt.Errorf("routing_key is required")
+	}
+	return validateIncident(ch, DefaultPagerDutyURL, []string{"critical", "error", "warning", "info"})
+}
+
+func (o *Opsgenie) Validate(ch config.Channel) error {
+	if ch.APIKey == "" {
+		return fmt.Errorf("api_key is required")
+	}
+	return validateIncident(ch, DefaultOpsgenieURL, []string{"P1", "P2", "P3", "P4", "P5"})
+}
+
+// validateIncident checks the url, priority map and retry settings shared by
+// the incident channels.
+func validateIncident(ch config.Channel, defaultURL string, allowed []string) error {
+	raw := incidentURL(ch, defaultURL)
+	if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("bad url %q", raw)
+	}
+	for level, v := range ch.Priority {
+		if l := strings.ToUpper(level); l != "CRITICAL" && l != "UNKNOWN" {
+			return fmt.Errorf("bad priority level %q (CRITICAL or UNKNOWN)", level)
+		}
+		if !slices.Contains(allowed, v) {
+			return fmt.Errorf("bad priority %q for %s (%s)", v, level, strings.Join(allowed, ", "))
+		}
+	}
+	_, err := parsePolicy(ch)
+	return err
+}
+
+func (p *PagerDuty) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := p.Validate(ch); err != nil {
+		return err
+	}
+	pol, _ := parsePolicy(ch)
+	type link struct {
+		Href string `json:"href"`
+		Text string `json:"text"`
+	}
+	type payload struct {
+		Summary       string            `json:"summary"`
+		Source        string            `json:"source"`
+		Severity      string            `json:"severity"`
+		Timestamp     string            `json:"timestamp"`
+		Component     string            `json:"component"`
+		Class         string            `json:"class"`
+		CustomDetails map[string]string `json:"custom_details"`
+	}
+	msg := struct {
+		RoutingKey  string   `json:"routing_key"`
+		EventAction string   `json:"event_action"`
+		DedupKey    string   `json:"dedup_key"`
+		Payload     *payload `json:"payload,omitempty"`
+		Links       []link   `json:"links,omitempty"`
+	}{RoutingKey: ch.RoutingKey, DedupKey: dedupKey(ev)}
+
+	switch {
+	case ev.Failed:
+		source := ev.Address
+		if source == "" {
+			source = ev.Host
+		}
+		msg.EventAction = "trigger"
+		msg.Payload = &payload{
+			Summary:       truncate(summary(ev), 1024),
+			Source:        source,
+			Severity:      priority(ch, ev, pagerDutySeverity),
+			Timestamp:     ev.CheckedAt.Format(time.RFC3339),
+			Component:     ev.Host,
+			Class:         string(ev.Check),
+			CustomDetails: details(ev),
+		}
+		if ev.DashboardURL != "" {
+			msg.Links = []link{{Href: ev.DashboardURL, Text: "Open dashboard"}}
+		}
+	case recovered(ev):
+		msg.EventAction = "resolve"
+	default:
+		return nil
+	}
+	return postJSON(ctx, p.client, pol, incidentURL(ch, DefaultPagerDutyURL), nil, msg)
+}
+
+func (o *Opsgenie) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := o.Validate(ch); err != nil {
+		return err
+	}
+	pol, _ := parsePolicy(ch)
+	header := http.Header{"Authorization": {"GenieKey " + ch.APIKey}}
+	alerts := strings.TrimSuffix(incidentURL(ch, DefaultOpsgenieURL), "/") + "/v2/alerts"
+	alias := dedupKey(ev)
+
+	switch {
+	case ev.Failed:
+		type responder struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		var responders []responder
+		for _, team := range ch.Responders {
+			responders = append(responders, responder{Name: team, Type: "team"})
+		}
+		return postJSON(ctx, o.client, pol, alerts, header, struct {
+			Message     string            `json:"message"`
+			Alias       string            `json:"alias"`
+			Description string            `json:"description,omitempty"`
+			Responders  []responder       `json:"responders,omitempty"`
+			Tags        []string          `json:"tags"`
+			Details     map[string]string `json:"details"`
+			Entity      string            `json:"entity"`
+			Source      string            `json:"source"`
+			Priority    string            `json:"priority"`
+		}{
+			// Opsgenie limits the message to 130 characters
+			Message:     truncate(summary(ev), 130),
+			Alias:       alias,
+			Description: truncate(ev.Message, 15000),
+			Responders:  responders,
+			Tags:        []string{string(ev.Check), ev.Level},
+			Details:     details(ev),
+			Entity:      ev.Host,
+			Source:      "simple-healthchecker",
+			Priority:    priority(ch, ev, opsgeniePriority),
+		})
+	case recovered(ev):
+		note := fmt.Sprintf("%s %s check recovered", ev.Host, ev.Check)
+		if ev.Outage > 0 {
+			note += " after " + roundOutage(ev.Outage)
+		}
+		return postJSON(ctx, o.client, pol, alerts+"/"+url.PathEscape(alias)+"/close?identifierType=alias", header, struct {
+			Source string `json:"source"`
+			Note   string `json:"note"`
+		}{"simple-healthchecker", note})
+	}
+	return nil
+}
+
+func incidentURL(ch config.Channel, def string) string {
+	if ch.URL == "" {
+		return def
+	}
+	return ch.URL
+}
+
+// dedupKey identifies the incident of a check.
+func dedupKey(ev Event) string { return fmt.Sprintf("%s/%s", ev.Host, ev.Check) }
+
+// recovered reports whether a passing event follows a failing level.
+func recovered(ev Event) bool {
+	return !ev.Failed && (ev.Previous == "CRITICAL" || ev.Previous == "UNKNOWN")
+}
+
+func summary(ev Event) string {
+	s := fmt.Sprintf("%s %s check is %s", ev.Host, ev.Check, ev.Level)
+	if ev.Message != "" {
+		s += ": " + ev.Message
+	}
+	return s
+}
+
+// priority looks the level of a failing event up in the channel's priority
+// map, falling back to defaults.
+func priority(ch config.Channel, ev Event, defaults map[string]string) string {
+	for level, p := range ch.Priority {
+		if strings.EqualFold(level, ev.Level) {
+			return p
+		}
+	}
+	if p, ok := defaults[ev.Level]; ok {
+		return p
+	}
+	return defaults["CRITICAL"]
+}
+
+// details are the custom details attached to an incident.
+func details(ev Event) map[string]string {
+	d := map[string]string{
+		"host":           ev.Host,
+		"check":          string(ev.Check),
+		"level":          ev.Level,
+		"previous_level": ev.Previous,
+		"message":        ev.Message,
+		"latency_ms":     fmt.Sprint(ev.LatencyMS),
+		"checked_at":     ev.CheckedAt.Format(time.RFC3339),
+	}
+	if ev.Address != "" {
+		d["address"] = ev.Address
+	}
+	if ev.URL != "" {
+		d["url"] = ev.URL
+	}
+	if ev.DashboardURL != "" {
+		d["dashboard_url"] = ev.DashboardURL
+	}
+	return d
+}
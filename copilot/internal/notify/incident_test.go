@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+func TestPagerDuty(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{Type: config.ChannelPagerDuty, URL: srv.URL + "/v2/enqueue", RoutingKey: "R0UT1NG"}
+	p := NewPagerDuty()
+
+	unknown := downEvent()
+	unknown.Level = "UNKNOWN"
+	passing := upEvent()
+	passing.Previous = "OK" // not a recovery
+	for _, ev := range []Event{downEvent(), unknown, upEvent(), passing} {
+		if err := p.Notify(context.Background(), ch, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ch.Priority = map[string]string{"critical": "warning"}
+	if err := p.Notify(context.Background(), ch, downEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := requests()
+	if len(got) != 4 {
+		t.Fatalf("got %d requests, want trigger, trigger, resolve and trigger", len(got))
+	}
+	var msgs []map[string]any
+	for _, r := range got {
+		if r.uri != "/v2/enqueue" {
+			t.Errorf("request to %s, want /v2/enqueue", r.uri)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(r.body, &m); err != nil {
+			t.Fatalf("body %s: %v", r.body, err)
+		}
+		msgs = append(msgs, m)
+	}
+
+	trigger := msgs[0]
+	if trigger["routing_key"] != "R0UT1NG" || trigger["event_action"] != "trigger" || trigger["dedup_key"] != "web-1/http" {
+		t.Errorf("trigger = %v", trigger)
+	}
+	if at(t, trigger, "payload", "summary") != "web-1 http check is CRITICAL: status 503 (expect 200)" ||
+		at(t, trigger, "payload", "source") != "10.0.0.5" || at(t, trigger, "payload", "severity") != "critical" ||
+		at(t, trigger, "payload", "custom_details", "url") != "https://web-1.example.com/health" ||
+		at(t, trigger, "links", 0, "href") != "https://status.example.com" {
+		t.Errorf("trigger payload = %v", trigger)
+	}
+	if got := at(t, msgs[1], "payload", "severity"); got != "error" {
+		t.Errorf("UNKNOWN severity = %v, want error", got)
+	}
+
+	resolve := msgs[2]
+	if resolve["event_action"] != "resolve" || resolve["dedup_key"] != trigger["dedup_key"] || resolve["payload"] != nil {
+		t.Errorf("resolve = %v, want the trigger's dedup key without a payload", resolve)
+	}
+	if got := at(t, msgs[3], "payload", "severity"); got != "warning" {
+		t.Errorf("severity = %v, want the channel's priority", got)
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{Type: config.ChannelOpsgenie, URL: srv.URL + "/", APIKey: "g3n1e", Responders: []string{"ops"}}
+	o := NewOpsgenie()
+	for _, ev := range []Event{downEvent(), upEvent()} {
+		if err := o.Notify(context.Background(), ch, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want create and close", len(got))
+	}
+	for _, r := range got {
+		if auth := r.header.Get("Authorization"); auth != "GenieKey g3n1e" {
+			t.Errorf("Authorization = %q", auth)
+		}
+	}
+
+	var create map[string]any
+	if err := json.Unmarshal(got[0].body, &create); err != nil {
+		t.Fatal(err)
+	}
+	if got[0].uri != "/v2/alerts" || create["alias"] != "web-1/http" || create["priority"] != "P1" || create["entity"] != "web-1" ||
+		at(t, create, "responders", 0, "name") != "ops" || at(t, create, "responders", 0, "type") != "team" ||
+		at(t, create, "tags", 1) != "CRITICAL" {
+		t.Errorf("create %s = %v", got[0].uri, create)
+	}
+
+	var closed map[string]any
+	if err := json.Unmarshal(got[1].body, &closed); err != nil {
+		t.Fatal(err)
+	}
+	if got[1].uri != "/v2/alerts/web-1%2Fhttp/close?identifierType=alias" || closed["note"] != "web-1 http check recovered after 5m30s" {
+		t.Errorf("close %s = %v, want the alert closed by its alias", got[1].uri, closed)
+	}
+}
@@ -0,0 +1,108 @@
+// Package notify sends check results to notification channels.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// Event is a check result as sent to notification channels. It is the
+// default webhook body and the data passed to body templates.
+type Event struct {
+	Host      string           `json:"host"`
+	Address   string           `json:"address"`
+	Check     config.CheckType `json:"check"`
+	URL       string           `json:"url,omitempty"`
+	Level     string           `json:"level"`
+	Previous  string           `json:"previous_level,omitempty"` // empty on the first run
+	Failed    bool             `json:"failed"`
+	Message   string           `json:"message"`
+	LatencyMS int64            `json:"latency_ms"`
+	CheckedAt time.Time        `json:"checked_at"`
+	// Outage is how long the check was failing, set when it recovers
+	Outage        time.Duration `json:"-"`
+	OutageSeconds int64         `json:"outage_seconds,omitempty"`
+	DashboardURL  string        `json:"dashboard_url,omitempty"`
+}
+
+// Notifier delivers events to one type of channel.
+type Notifier interface {
+	Type() config.ChannelType
+	// Validate checks the channel settings before anything is sent.
+	Validate(ch config.Channel) error
+	Notify(ctx context.Context, ch config.Channel, ev Event) error
+}
+
+// Registry maps channel types to their notifiers.
+type Registry struct {
+	notifiers map[config.ChannelType]Notifier
+}
+
+func NewRegistry() *Registry {
+	return &Registry{notifiers: make(map[config.ChannelType]Notifier)}
+}
+
+// Default returns a registry with all built-in notifiers.
+func Default() *Registry {
+	r := NewRegistry()
+	r.Register(NewWebhook())
+	r.Register(NewHealthchecks())
+	r.Register(NewChat(config.ChannelSlack, slackMessage))
+	r.Register(NewChat(config.ChannelDiscord, discordMessage))
+	r.Register(NewChat(config.ChannelTeams, teamsMessage))
+	r.Register(NewEmail())
+	r.Register(NewPagerDuty())
+	r.Register(NewOpsgenie())
+	r.Register(NewNtfy())
+	r.Register(NewGotify())
+	r.Register(NewPushover())
+	return r
+}
+
+func (r *Registry) Register(n Notifier) {
+	r.notifiers[n.Type()] = n
+}
+
+func (r *Registry) Get(t config.ChannelType) (Notifier, error) {
+	n, ok := r.notifiers[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification channel type %q", t)
+	}
+	return n, nil
+}
+
+// Validate checks that every channel has a known type and valid settings.
+func (r *Registry) Validate(channels []config.Channel) error {
+	for _, ch := range channels {
+		n, err := r.Get(ch.Type)
+		if err != nil {
+			return fmt.Errorf("notification channel %s: %w", ch.Name, err)
+		}
+		if err := n.Validate(ch); err != nil {
+			return fmt.Errorf("notification channel %s: %w", ch.Name, err)
+		}
+	}
+	return nil
+}
+
+// Notify sends an event to a channel.
+func (r *Registry) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	n, err := r.Get(ch.Type)
+	if err != nil {
+		return err
+	}
+	return n.Notify(ctx, ch, ev)
+}
+
+// Flush sends anything notifiers are holding back, such as email digests.
+// It is called on shutdown.
+func (r *Registry) Flush() {
+	for _, n := range r.notifiers {
+		if f, ok := n.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// request is a request received by a test server.
+type request struct {
+	method string
+	uri    string // path and query
+	header http.Header
+	body   []byte
+}
+
+// recorder starts a server that records requests and answers them with the
+// given statuses in turn, then with 200.
+func recorder(t *testing.T, statuses ...int) (*httptest.Server, func() []request) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, request{r.Method, r.URL.RequestURI(), r.Header.Clone(), body})
+		status := http.StatusOK
+		if len(got) <= len(statuses) {
+			status = statuses[len(got)-1]
+		}
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]request(nil), got...)
+	}
+}
+
+// downEvent and upEvent are a check failing and recovering 5m30s later.
+func downEvent() Event {
+	return Event{
+		Host: "web-1", Address: "10.0.0.5", Check: config.CheckHTTP, URL: "https://web-1.example.com/health",
+		Level: "CRITICAL", Previous: "OK", Failed: true, Message: "status 503 (expect 200)", LatencyMS: 1500,
+		CheckedAt: time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC), DashboardURL: "https://status.example.com",
+	}
+}
+
+func upEvent() Event {
+	ev := downEvent()
+	ev.Level, ev.Previous, ev.Failed, ev.Message, ev.LatencyMS = "OK", "CRITICAL", false, "status 200 (expect 200)", 42
+	ev.CheckedAt = ev.CheckedAt.Add(5*time.Minute + 30*time.Second)
+	ev.Outage, ev.OutageSeconds = 5*time.Minute+30*time.Second, 330
+	return ev
+}
+
+func intPtr(n int) *int { return &n }
+
+// flushNotifier counts flushes.
+type flushNotifier struct {
+	Webhook
+	flushed int
+}
+
+func (f *flushNotifier) Type() config.ChannelType { return "flushing" }
+func (f *flushNotifier) Flush()                   { f.flushed++ }
+
+func TestRegistry(t *testing.T) {
+	r := Default()
+	for _, typ := range []config.ChannelType{
+		config.ChannelWebhook, config.ChannelHealthchecks, config.ChannelSlack, config.ChannelDiscord,
+		config.ChannelTeams, config.ChannelEmail, config.ChannelPagerDuty, config.ChannelOpsgenie,
+		config.ChannelNtfy, config.ChannelGotify, config.ChannelPushover,
+	} {
+		if n, err := r.Get(typ); err != nil || n.Type() != typ {
+			t.Errorf("Get(%s) = %v, %v", typ, n, err)
+		}
+	}
+
+	if _, err := r.Get("pigeon"); err == nil {
+		t.Error("Get of an unknown type succeeded")
+	}
+	err := r.Validate([]config.Channel{
+		{Name: "ok", Type: config.ChannelWebhook, URL: "https://example.com/hook"},
+		{Name: "broken", Type: config.ChannelWebhook, URL: "ftp://example.com"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "notification channel broken") {
+		t.Errorf("Validate err = %v, want it to name the broken channel", err)
+	}
+	if err := r.Validate([]config.Channel{{Name: "carrier", Type: "pigeon"}}); err == nil {
+		t.Error("Validate accepted an unknown type")
+	}
+
+	srv, requests := recorder(t)
+	if err := r.Notify(context.Background(), config.Channel{Type: config.ChannelWebhook, URL: srv.URL}, downEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("Notify made %d requests, want 1", len(requests()))
+	}
+
+	f := &flushNotifier{}
+	r.Register(f)
+	r.Flush()
+	if f.flushed != 1 {
+		t.Errorf("Flush flushed %d times, want 1", f.flushed)
+	}
+}
+
+func TestWebhookBody(t *testing.T) {
+	srv, requests := recorder(t)
+	w := NewWebhook()
+	if err := w.Notify(context.Background(), config.Channel{URL: srv.URL}, downEvent()); err != nil {
+		t.Fatal(err)
+	}
+	templated := config.Channel{
+		URL:     srv.URL + "/alert?src=hc",
+		Method:  "put",
+		Body:    `{"text": {{json (printf "%s %s is %s" .Host .Check .Level)}}, "down": {{.Failed}}}`,
+		Headers: map[string]string{"Authorization": "Bearer t0ken"},
+	}
+	if err := w.Notify(context.Background(), templated, downEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want 2", len(got))
+	}
+	var ev Event
+	if err := json.Unmarshal(got[0].body, &ev); err != nil {
+		t.Fatalf("default body %s: %v", got[0].body, err)
+	}
+	if got[0].method != http.MethodPost || got[0].header.Get("Content-Type") != "application/json" ||
+		ev.Host != "web-1" || ev.Level != "CRITICAL" || ev.Previous != "OK" || !ev.Failed || ev.LatencyMS != 1500 {
+		t.Errorf("default request %s %s, event %+v", got[0].method, got[0].header, ev)
+	}
+
+	want := `{"text": "web-1 http is CRITICAL", "down": true}`
+	if r := got[1]; r.method != http.MethodPut || r.uri != "/alert?src=hc" || string(r.body) != want || r.header.Get("Authorization") != "Bearer t0ken" {
+		t.Errorf("templated request %s %s %q %v, want PUT with body %s", r.method, r.uri, r.body, r.header, want)
+	}
+
+	if err := w.Validate(config.Channel{URL: srv.URL, Body: "{{.Host"}); err == nil {
+		t.Error("Validate accepted a broken template")
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	srv, requests := recorder(t)
+	w := NewWebhook()
+	for _, ch := range []config.Channel{
+		{URL: srv.URL, Secret: "s3cret"},
+		{URL: srv.URL, Secret: "s3cret", SignatureHeader: "X-Hub-Signature-256"},
+		{URL: srv.URL},
+	} {
+		if err := w.Notify(context.Background(), ch, downEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := requests()
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(got[0].body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if sig := got[0].header.Get(DefaultSignatureHeader); sig != want {
+		t.Errorf("signature = %q, want %q", sig, want)
+	}
+	if sig := got[1].header.Get("X-Hub-Signature-256"); sig != want {
+		t.Errorf("custom header signature = %q, want %q", sig, want)
+	}
+	if sig := got[2].header.Get(DefaultSignatureHeader); sig != "" {
+		t.Errorf("unsigned request has signature %q", sig)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	cases := []struct {
+		name     string
+		retries  *int
+		statuses []int
+		requests int
+		err      string
+	}{
+		{"recovers", nil, []int{500, 503}, 3, ""},
+		{"rate limited", nil, []int{429}, 2, ""},
+		{"gives up", intPtr(2), []int{500, 500, 500, 500}, 3, "after 3 attempt(s): status 500"},
+		{"no retries", intPtr(0), []int{502}, 1, "after 1 attempt(s): status 502"},
+		{"client error", nil, []int{400}, 1, "status 400"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv, requests := recorder(t, c.statuses...)
+			ch := config.Channel{URL: srv.URL, Retries: c.retries, RetryDelay: "1ms"}
+			err := NewWebhook().Notify(context.Background(), ch, downEvent())
+			if (err == nil) != (c.err == "") || (err != nil && !strings.Contains(err.Error(), c.err)) {
+				t.Errorf("err = %v, want %q", err, c.err)
+			}
+			if n := len(requests()); n != c.requests {
+				t.Errorf("requests = %d, want %d", n, c.requests)
+			}
+		})
+	}
+}
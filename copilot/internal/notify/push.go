@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// DefaultPushoverURL is the Pushover API, overridden by the channel url.
+const DefaultPushoverURL = "https://api.pushover.net/1"
+
+// Push channels send failing checks with high priority by default and
+// recoveries with the normal priority of the service.
+var (
+	ntfyPriority     = map[string]string{"CRITICAL": "high", "UNKNOWN": "high"}
+	gotifyPriority   = map[string]string{"CRITICAL": "8", "UNKNOWN": "8"}
+	pushoverPriority = map[string]string{"CRITICAL": "1", "UNKNOWN": "1"}
+)
+
+// Ntfy publishes events to an ntfy topic, on ntfy.sh or a self-hosted server.
+type Ntfy struct{ client *http.Client }
+
+// Gotify sends events as messages of a Gotify application.
+type Gotify struct{ client *http.Client }
+
+// Pushover sends events to a Pushover user or group. Emergency notifications
+// (priority 2) repeat until acknowledged; the recovery of the check cancels
+// them.
+type Pushover struct{ client *http.Client }
+
+func NewNtfy() *Ntfy         { return &Ntfy{client: &http.Client{}} }
+func NewGotify() *Gotify     { return &Gotify{client: &http.Client{}} }
+func NewPushover() *Pushover { return &Pushover{client: &http.Client{}} }
+
+func (n *Ntfy) Type() config.ChannelType     { return config.ChannelNtfy }
+func (g *Gotify) Type() config.ChannelType   { return config.ChannelGotify }
+func (p *Pushover) Type() config.ChannelType { return config.ChannelPushover }
+
+func (n *Ntfy) Validate(ch config.Channel) error {
+	if _, _, err := ntfyTopic(ch.URL); err != nil {
+		return err
+	}
+	return validatePush(ch, parseNtfyPriority)
+}
+
+func (g *Gotify) Validate(ch config.Channel) error {
+	if u, err := url.Parse(ch.URL); ch.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("bad url %q", ch.URL)
+	}
+	if ch.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	return validatePush(ch, rangedInt(0, 10))
+}
+
+func (p *Pushover) Validate(ch config.Channel) error {
+	if ch.Token == "" || ch.UserKey == "" {
+		return fmt.Errorf("token and user_key are required")
+	}
+	if ch.URL != "" {
+		if u, err := url.Parse(ch.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("bad url %q", ch.URL)
+		}
+	}
+	if _, _, err := emergency(ch); err != nil {
+		return err
+	}
+	return validatePush(ch, rangedInt(-2, 2))
+}
+
+// validatePush checks the priority map and retry settings of a push channel.
+func validatePush(ch config.Channel, parse func(string) (int, error)) error {
+	for level, v := range ch.Priority {
+		if l := strings.ToUpper(level); l != "CRITICAL" && l != "UNKNOWN" {
+			return fmt.Errorf("bad priority level %q (CRITICAL or UNKNOWN)", level)
+		}
+		if _, err := parse(v); err != nil {
+			return fmt.Errorf("bad priority %q for %s", v, level)
+		}
+	}
+	_, err := parsePolicy(ch)
+	return err
+}
+
+func parseNtfyPriority(s string) (int, error) {
+	names := map[string]int{"min": 1, "low": 2, "default": 3, "high": 4, "max": 5, "urgent": 5}
+	if p, ok := names[strings.ToLower(s)]; ok {
+		return p, nil
+	}
+	return rangedInt(1, 5)(s)
+}
+
+func rangedInt(lo, hi int) func(string) (int, error) {
+	return func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("%q is not %d to %d", s, lo, hi)
+		}
+		return n, nil
+	}
+}
+
+// ntfyTopic splits a topic URL into the server URL, which JSON messages are
+// published to, and the topic.
+func ntfyTopic(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if raw == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("bad url %q", raw)
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	topic := path[i+1:]
+	if topic == "" {
+		return "", "", fmt.Errorf("url %q has no topic", raw)
+	}
+	u.Path, u.RawQuery = "/"+path[:i+1], ""
+	return u.String(), topic, nil
+}
+
+// emergency returns how often and for how long Pushover repeats an emergency
+// notification.
+func emergency(ch config.Channel) (retry, expire time.Duration, err error) {
+	retry, expire = time.Minute, time.Hour
+	if ch.EmergencyRetry != "" {
+		if retry, err = time.ParseDuration(ch.EmergencyRetry); err != nil || retry < 30*time.Second {
+			return 0, 0, fmt.Errorf("bad emergency_retry %q (at least 30s)", ch.EmergencyRetry)
+		}
+	}
+	if ch.EmergencyExpire != "" {
+		if expire, err = time.ParseDuration(ch.EmergencyExpire); err != nil || expire <= 0 || expire > 3*time.Hour {
+			return 0, 0, fmt.Errorf("bad emergency_expire %q (at most 3h)", ch.EmergencyExpire)
+		}
+	}
+	return retry, expire, nil
+}
+
+// pushBody is the text of a push notification: the check message and the
+// facts not already in the title.
+func pushBody(ev Event) string {
+	var lines []string
+	if ev.Message != "" {
+		lines = append(lines, ev.Message)
+	}
+	for _, f := range facts(ev) {
+		if f.name != "Host" && f.name != "Check" {
+			lines = append(lines, f.name+": "+f.value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (n *Ntfy) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := n.Validate(ch); err != nil {
+		return err
+	}
+	if !ev.Failed && !recovered(ev) {
+		return nil
+	}
+	pol, _ := parsePolicy(ch)
+	server, topic, _ := ntfyTopic(ch.URL)
+	msg := struct {
+		Topic    string   `json:"topic"`
+		Title    string   `json:"title"`
+		Message  string   `json:"message"`
+		Priority int      `json:"priority,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		Click    string   `json:"click,omitempty"`
+	}{Topic: topic, Title: title(ev), Message: pushBody(ev), Tags: ch.Tags, Click: ev.DashboardURL}
+	if ev.Failed {
+		msg.Priority, _ = parseNtfyPriority(priority(ch, ev, ntfyPriority))
+	}
+	header := http.Header{}
+	switch {
+	case ch.Token != "":
+		header.Set("Authorization", "Bearer "+ch.Token)
+	case ch.Username != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(ch.Username+":"+ch.Password)))
+	}
+	return postJSON(ctx, n.client, pol, server, header, msg)
+}
+
+func (g *Gotify) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := g.Validate(ch); err != nil {
+		return err
+	}
+	if !ev.Failed && !recovered(ev) {
+		return nil
+	}
+	pol, _ := parsePolicy(ch)
+	msg := struct {
+		Title    string         `json:"title"`
+		Message  string         `json:"message"`
+		Priority *int           `json:"priority,omitempty"` // nil uses the app's default
+		Extras   map[string]any `json:"extras,omitempty"`
+	}{Title: title(ev), Message: pushBody(ev)}
+	if ev.Failed {
+		p, _ := strconv.Atoi(priority(ch, ev, gotifyPriority))
+		msg.Priority = &p
+	}
+	if ev.DashboardURL != "" {
+		msg.Extras = map[string]any{"client::notification": map[string]any{"click": map[string]string{"url": ev.DashboardURL}}}
+	}
+	header := http.Header{"X-Gotify-Key": {ch.Token}}
+	return postJSON(ctx, g.client, pol, strings.TrimSuffix(ch.URL, "/")+"/message", header, msg)
+}
+
+func (p *Pushover) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	if err := p.Validate(ch); err != nil {
+		return err
+	}
+	if !ev.Failed && !recovered(ev) {
+		return nil
+	}
+	pol, _ := parsePolicy(ch)
+	api := strings.TrimSuffix(incidentURL(ch, DefaultPushoverURL), "/")
+	tag := dedupKey(ev)
+	form := url.Values{
+		"token":   {ch.Token},
+		"user":    {ch.UserKey},
+		"title":   {truncate(title(ev), 250)},
+		"message": {truncate(pushBody(ev), 1024)},
+	}
+	if !ev.CheckedAt.IsZero() {
+		form.Set("timestamp", strconv.FormatInt(ev.CheckedAt.Unix(), 10))
+	}
+	if ev.DashboardURL != "" {
+		form.Set("url", ev.DashboardURL)
+		form.Set("url_title", "Open dashboard")
+	}
+
+	var cancelErr error
+	if ev.Failed {
+		prio := priority(ch, ev, pushoverPriority)
+		form.Set("priority", prio)
+		if prio == "2" {
+			retry, expire, _ := emergency(ch)
+			form.Set("retry", strconv.Itoa(int(retry.Seconds())))
+			form.Set("expire", strconv.Itoa(int(expire.Seconds())))
+			form.Set("tags", tag)
+		}
+	} else if emergencyConfigured(ch) {
+		// stop the phone repeating the emergency notification of the outage
+		cancel := url.Values{"token": {ch.Token}}
+		if err := p.post(ctx, pol, api+"/receipts/cancel_by_tag/"+url.PathEscape(tag)+".json", cancel); err != nil {
+			cancelErr = fmt.Errorf("cancel emergency: %w", err)
+		}
+	}
+	return errors.Join(cancelErr, p.post(ctx, pol, api+"/messages.json", form))
+}
+
+// emergencyConfigured reports whether any failing level is sent with
+// Pushover emergency priority.
+func emergencyConfigured(ch config.Channel) bool {
+	for _, level := range []string{"CRITICAL", "UNKNOWN"} {
+		if priority(ch, Event{Level: level}, pushoverPriority) == "2" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pushover) post(ctx context.Context, pol policy, target string, form url.Values) error {
+	body := form.Encode()
+	return send(ctx, p.client, pol, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// notifyAll sends events through n, failing the test on an error.
+func notifyAll(t *testing.T, n Notifier, ch config.Channel, events ...Event) {
+	t.Helper()
+	for _, ev := range events {
+		if err := n.Notify(context.Background(), ch, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// passing is a passing event that is not a recovery, which push channels skip.
+func passing() Event {
+	ev := upEvent()
+	ev.Previous, ev.Outage, ev.OutageSeconds = "OK", 0, 0
+	return ev
+}
+
+func TestNtfy(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{Type: config.ChannelNtfy, URL: srv.URL + "/homelab-alerts", Token: "tk_s3cret", Tags: []string{"warning"}}
+	notifyAll(t, NewNtfy(), ch, downEvent(), passing(), upEvent())
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want the failure and the recovery", len(got))
+	}
+	var msgs []map[string]any
+	for _, r := range got {
+		if r.uri != "/" || r.header.Get("Authorization") != "Bearer tk_s3cret" {
+			t.Errorf("request to %s with Authorization %q, want the server root with the token", r.uri, r.header.Get("Authorization"))
+		}
+		var m map[string]any
+		if err := json.Unmarshal(r.body, &m); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, m)
+	}
+	down := msgs[0]
+	if down["topic"] != "homelab-alerts" || down["title"] != "🔴 web-1 http check is CRITICAL" || down["priority"] != float64(4) ||
+		down["click"] != "https://status.example.com" || at(t, down, "tags", 0) != "warning" {
+		t.Errorf("down message = %v", down)
+	}
+	if want := "status 503 (expect 200)\nLevel: OK → CRITICAL\nLatency: 1500ms"; down["message"] != want {
+		t.Errorf("message = %q, want %q", down["message"], want)
+	}
+	if up := msgs[1]; up["priority"] != nil || up["title"] != "✅ web-1 http check recovered after 5m30s" {
+		t.Errorf("recovery = %v, want the default priority", up)
+	}
+
+	basic := config.Channel{Type: config.ChannelNtfy, URL: srv.URL + "/alerts", Username: "hc", Password: "pw", Priority: map[string]string{"CRITICAL": "urgent"}}
+	notifyAll(t, NewNtfy(), basic, downEvent())
+	r := requests()[2]
+	if user, pass, ok := (&http.Request{Header: r.header}).BasicAuth(); !ok || user != "hc" || pass != "pw" {
+		t.Errorf("Authorization = %q, want basic auth", r.header.Get("Authorization"))
+	}
+	var m map[string]any
+	json.Unmarshal(r.body, &m)
+	if m["priority"] != float64(5) {
+		t.Errorf("priority = %v, want the channel's urgent", m["priority"])
+	}
+}
+
+func TestGotify(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{Type: config.ChannelGotify, URL: srv.URL + "/", Token: "AppT0ken"}
+	notifyAll(t, NewGotify(), ch, downEvent(), passing(), upEvent())
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want the failure and the recovery", len(got))
+	}
+	for _, r := range got {
+		if r.uri != "/message" || r.header.Get("X-Gotify-Key") != "AppT0ken" {
+			t.Errorf("request to %s with key %q", r.uri, r.header.Get("X-Gotify-Key"))
+		}
+	}
+	var down, up map[string]any
+	json.Unmarshal(got[0].body, &down)
+	json.Unmarshal(got[1].body, &up)
+	if down["priority"] != float64(8) || down["title"] != "🔴 web-1 http check is CRITICAL" ||
+		at(t, down, "extras", "client::notification", "click", "url") != "https://status.example.com" {
+		t.Errorf("down message = %v", down)
+	}
+	if _, ok := up["priority"]; ok {
+		t.Errorf("recovery = %v, want the app's default priority", up)
+	}
+}
+
+func TestPushover(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{Type: config.ChannelPushover, URL: srv.URL, Token: "app", UserKey: "user"}
+	notifyAll(t, NewPushover(), ch, downEvent(), passing(), upEvent())
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want the failure and the recovery without a cancel", len(got))
+	}
+	forms := pushoverForms(t, got)
+	if down := forms[0]; got[0].uri != "/messages.json" || down.Get("token") != "app" || down.Get("user") != "user" ||
+		down.Get("priority") != "1" || down.Get("timestamp") != "1740909600" || down.Get("url") != "https://status.example.com" ||
+		down.Get("retry") != "" || down.Get("tags") != "" {
+		t.Errorf("down message %s = %v", got[0].uri, down)
+	}
+	if up := forms[1]; got[1].uri != "/messages.json" || up.Get("priority") != "" {
+		t.Errorf("recovery %s = %v", got[1].uri, up)
+	}
+}
+
+func TestPushoverEmergency(t *testing.T) {
+	srv, requests := recorder(t)
+	ch := config.Channel{
+		Type: config.ChannelPushover, URL: srv.URL, Token: "app", UserKey: "user",
+		Priority: map[string]string{"critical": "2"}, EmergencyRetry: "2m",
+	}
+	notifyAll(t, NewPushover(), ch, downEvent(), upEvent())
+
+	got := requests()
+	uris := []string{"/messages.json", "/receipts/cancel_by_tag/web-1%2Fhttp.json", "/messages.json"}
+	if len(got) != len(uris) {
+		t.Fatalf("got %d requests, want the emergency, its cancel and the recovery", len(got))
+	}
+	for i, r := range got {
+		if r.uri != uris[i] {
+			t.Errorf("request %d to %s, want %s", i, r.uri, uris[i])
+		}
+	}
+	forms := pushoverForms(t, got)
+	if down := forms[0]; down.Get("priority") != "2" || down.Get("retry") != "120" || down.Get("expire") != "3600" || down.Get("tags") != "web-1/http" {
+		t.Errorf("emergency message = %v", down)
+	}
+	if cancel := forms[1]; len(cancel) != 1 || cancel.Get("token") != "app" {
+		t.Errorf("cancel = %v, want only the app token", cancel)
+	}
+	if up := forms[2]; up.Get("priority") != "" || up.Get("tags") != "" {
+		t.Errorf("recovery = %v, want a normal message", up)
+	}
+}
+
+func pushoverForms(t *testing.T, got []request) []url.Values {
+	t.Helper()
+	var forms []url.Values
+	for _, r := range got {
+		if ct := r.header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q, want a form", ct)
+		}
+		form, err := url.ParseQuery(string(r.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		forms = append(forms, form)
+	}
+	return forms
+}
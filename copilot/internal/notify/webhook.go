@@ -0,0 +1,207 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+const (
+	DefaultSignatureHeader = "X-Healthchecker-Signature"
+	DefaultRetries         = 3
+	DefaultRetryDelay      = time.Second
+	DefaultTimeout         = 10 * time.Second
+)
+
+// funcs are available in body templates; json quotes and escapes a value.
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Webhook posts events to a URL, optionally signed and with a templated body.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook() *Webhook {
+	return &Webhook{client: &http.Client{}}
+}
+
+func (w *Webhook) Type() config.ChannelType { return config.ChannelWebhook }
+
+func (w *Webhook) Validate(ch config.Channel) error {
+	_, _, err := parseWebhook(ch)
+	return err
+}
+
+func (w *Webhook) Notify(ctx context.Context, ch config.Channel, ev Event) error {
+	tmpl, p, err := parseWebhook(ch)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	if tmpl == nil {
+		body, err = json.Marshal(ev)
+	} else {
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, ev)
+		body = buf.Bytes()
+	}
+	if err != nil {
+		return fmt.Errorf("render body: %w", err)
+	}
+	method := strings.ToUpper(ch.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	sigHeader := ch.SignatureHeader
+	if sigHeader == "" {
+		sigHeader = DefaultSignatureHeader
+	}
+	return send(ctx, w.client, p, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, ch.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range ch.Headers {
+			req.Header.Set(k, v)
+		}
+		if ch.Secret != "" {
+			req.Header.Set(sigHeader, Sign(ch.Secret, body))
+		}
+		return req, nil
+	})
+}
+
+// Sign returns the signature header value for a body: sha256=<hex HMAC>.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseWebhook(ch config.Channel) (*template.Template, policy, error) {
+	u, err := url.Parse(ch.URL)
+	if ch.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, policy{}, fmt.Errorf("bad url %q", ch.URL)
+	}
+	var tmpl *template.Template
+	if ch.Body != "" {
+		if tmpl, err = template.New("body").Funcs(funcs).Parse(ch.Body); err != nil {
+			return nil, policy{}, fmt.Errorf("bad body template: %w", err)
+		}
+	}
+	p, err := parsePolicy(ch)
+	return tmpl, p, err
+}
+
+// policy is how failed deliveries are retried.
+type policy struct {
+	retries int
+	delay   time.Duration // before the first retry, doubled for each further one
+	timeout time.Duration // per attempt
+}
+
+func parsePolicy(ch config.Channel) (policy, error) {
+	p := policy{retries: DefaultRetries, delay: DefaultRetryDelay, timeout: DefaultTimeout}
+	if ch.Retries != nil {
+		if *ch.Retries < 0 {
+			return p, fmt.Errorf("retries must not be negative")
+		}
+		p.retries = *ch.Retries
+	}
+	if ch.RetryDelay != "" {
+		d, err := time.ParseDuration(ch.RetryDelay)
+		if err != nil || d < 0 {
+			return p, fmt.Errorf("bad retry_delay %q", ch.RetryDelay)
+		}
+		p.delay = d
+	}
+	if ch.Timeout != "" {
+		d, err := time.ParseDuration(ch.Timeout)
+		if err != nil || d <= 0 {
+			return p, fmt.Errorf("bad timeout %q", ch.Timeout)
+		}
+		p.timeout = d
+	}
+	return p, nil
+}
+
+// send makes a request, retrying with exponential backoff on network errors,
+// 429 and 5xx responses. Other error responses are not retried.
+func send(ctx context.Context, client *http.Client, p policy, newReq func(context.Context) (*http.Request, error)) error {
+	delay := p.delay
+	for attempt := 0; ; attempt++ {
+		retry, err := attemptOnce(ctx, client, p.timeout, newReq)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= p.retries {
+			return fmt.Errorf("after %d attempt(s): %w", attempt+1, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// postJSON sends v as JSON to url with the extra headers, retrying as send does.
+func postJSON(ctx context.Context, client *http.Client, p policy, url string, header http.Header, v any) error {
+	// no HTML escaping: Slack links and check messages contain <, > and &
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	body := buf.Bytes()
+	return send(ctx, client, p, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for k, vs := range header {
+			req.Header[k] = vs
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
+
+func attemptOnce(ctx context.Context, client *http.Client, timeout time.Duration, newReq func(context.Context) (*http.Request, error)) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := newReq(ctx)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return false, nil
+}
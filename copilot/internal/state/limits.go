@@ -0,0 +1,108 @@
+package state
+
+import (
+	"context"
+	"hash/fnv"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+// targetLimiter limits how many checks run at once against the same address
+// or domain, and how soon after each other they may start.
+type targetLimiter struct {
+	concurrency int           // 0 means no limit
+	interval    time.Duration // 0 means no limit
+	mu          sync.Mutex
+	cond        *sync.Cond
+	inFlight    map[string]int
+	next        map[string]time.Time // earliest start of the next check per target
+}
+
+func newTargetLimiter(concurrency int, interval time.Duration) *targetLimiter {
+	l := &targetLimiter{
+		concurrency: concurrency,
+		interval:    interval,
+		inFlight:    make(map[string]int),
+		next:        make(map[string]time.Time),
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a check against key may start and returns a func to
+// call when it has finished. It gives up with ctx's error when ctx is done
+// first.
+func (l *targetLimiter) acquire(ctx context.Context, key string) (func(), error) {
+	// wake the waiters below when ctx is done
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+
+	l.mu.Lock()
+	for l.concurrency > 0 && l.inFlight[key] >= l.concurrency && ctx.Err() == nil {
+		l.cond.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		l.mu.Unlock()
+		return nil, err
+	}
+	l.inFlight[key]++
+	start := time.Now()
+	if next := l.next[key]; next.After(start) {
+		start = next
+	}
+	if l.interval > 0 {
+		l.next[key] = start.Add(l.interval)
+	}
+	l.mu.Unlock()
+
+	release := func() {
+		l.mu.Lock()
+		if l.inFlight[key]--; l.inFlight[key] == 0 {
+			delete(l.inFlight, key)
+		}
+		if !l.next[key].After(time.Now()) {
+			delete(l.next, key)
+		}
+		l.mu.Unlock()
+		l.cond.Broadcast()
+	}
+	t := time.NewTimer(time.Until(start))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return release, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// targetKey is the address or domain a check runs against: the URL host for
+// http checks, otherwise the host address.
+func targetKey(j job) string {
+	if j.check.Type == config.CheckHTTP && j.check.URL != "" {
+		if u, err := url.Parse(j.check.URL); err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	return strings.ToLower(j.address)
+}
+
+// phase is a fixed offset within interval for a check, derived from its host
+// and target, so that checks sharing an interval are spread across it.
+func phase(host string, c *CheckStatus, interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(host + "\x00" + string(c.Type) + "\x00" + c.URL))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
@@ -0,0 +1,153 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+)
+
+func TestTargetLimiterConcurrency(t *testing.T) {
+	l := newTargetLimiter(1, 0)
+	release, err := l.acquire(context.Background(), "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// other targets are not held up
+	other, err := l.acquire(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	acquired := make(chan func())
+	go func() {
+		r, _ := l.acquire(context.Background(), "db")
+		acquired <- r
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second check against db started while the first was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case r := <-acquired:
+		r()
+	case <-time.After(time.Second):
+		t.Fatal("second check against db did not start after the first finished")
+	}
+}
+
+func TestTargetLimiterInterval(t *testing.T) {
+	l := newTargetLimiter(0, 100*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(context.Background(), "db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 checks started within %v, want them 100ms apart", elapsed)
+	}
+}
+
+func TestTargetLimiterCancel(t *testing.T) {
+	l := newTargetLimiter(1, 0)
+	release, _ := l.acquire(context.Background(), "db")
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "db"); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	l = newTargetLimiter(0, time.Hour)
+	r, _ := l.acquire(context.Background(), "db")
+	r()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "db"); err != context.DeadlineExceeded {
+		t.Errorf("err = %v while waiting for the interval, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTargetKey(t *testing.T) {
+	cases := []struct {
+		j    job
+		want string
+	}{
+		{job{address: "Router.LAN", check: CheckStatus{Type: config.CheckPing}}, "router.lan"},
+		{job{address: "10.0.0.1", check: CheckStatus{Type: config.CheckHTTP, URL: "https://API.example.com:8443/health"}}, "api.example.com"},
+		{job{address: "10.0.0.1", check: CheckStatus{Type: config.CheckHTTP}}, "10.0.0.1"},
+	}
+	for _, c := range cases {
+		if got := targetKey(c.j); got != c.want {
+			t.Errorf("targetKey(%+v) = %q, want %q", c.j, got, c.want)
+		}
+	}
+}
+
+func TestDueLockedSpreadsPhases(t *testing.T) {
+	now := time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC)
+	st := New(&config.Config{})
+	st.interval = time.Minute
+
+	first := make(map[string]time.Time)
+	distinct := make(map[time.Time]bool)
+	for i := 0; i < 20; i++ {
+		host := fmt.Sprintf("host-%d", i)
+		c := &CheckStatus{Type: config.CheckPing}
+		if st.dueLocked(host, c, now) {
+			t.Errorf("%s is due before its phase", host)
+		}
+		if c.NextRun.Before(now) || !c.NextRun.Before(now.Add(time.Minute)) {
+			t.Errorf("%s first runs at %v, want within the first interval", host, c.NextRun)
+		}
+		first[host] = c.NextRun
+		distinct[c.NextRun] = true
+
+		// due once its phase is reached, then every interval
+		if !st.dueLocked(host, c, c.NextRun) || !c.NextRun.Equal(first[host].Add(time.Minute)) {
+			t.Errorf("%s next run = %v, want one interval after its phase", host, c.NextRun)
+		}
+	}
+	if len(distinct) < 10 {
+		t.Errorf("20 checks share %d start times, want them spread across the interval", len(distinct))
+	}
+
+	// the phase of a check does not change between runs of the program
+	again := New(&config.Config{})
+	again.interval = time.Minute
+	c := &CheckStatus{Type: config.CheckPing}
+	again.dueLocked("host-3", c, now)
+	if !c.NextRun.Equal(first["host-3"]) {
+		t.Errorf("phase changed from %v to %v", first["host-3"], c.NextRun)
+	}
+
+	// cron checks run at their slot, not at a phase
+	cron := &CheckStatus{Type: config.CheckPing, Schedule: "*/5 * * * *"}
+	st.dueLocked("host-1", cron, now.Add(time.Second))
+	if want := now.Add(5 * time.Minute); !cron.NextRun.Equal(want) {
+		t.Errorf("cron next run = %v, want %v", cron.NextRun, want)
+	}
+
+	// a schedule without a next time never runs
+	idle := &CheckStatus{Type: config.CheckPing, sched: never{}}
+	for i := 0; i < 3; i++ {
+		if st.dueLocked("host-1", idle, now.Add(time.Duration(i)*time.Second)) {
+			t.Fatal("check with a schedule that never fires is due")
+		}
+	}
+}
+
+// never is a schedule that never fires.
+type never struct{}
+
+func (never) Next(time.Time) time.Time { return time.Time{} }
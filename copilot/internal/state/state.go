@@ -2,9 +2,9 @@ package state
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -16,17 +16,43 @@ import (
 
 	"github.com/andrewsjg/simple-healthchecker/copilot/internal/checks"
 	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/notify"
 )
 
+// Level is the health reported by the last run of a check.
+type Level string
+
+const (
+	LevelOK       Level = "OK"
+	LevelWarning  Level = "WARNING" // passed, but slow or degraded
+	LevelCritical Level = "CRITICAL"
+	LevelUnknown  Level = "UNKNOWN" // the check could not run
+)
+
+// Failed reports whether the level counts as a failure. WARNING does not.
+func (l Level) Failed() bool {
+	return l != LevelOK && l != LevelWarning
+}
+
 type CheckStatus struct {
 	Type      config.CheckType
 	Enabled   bool
-	OK        bool
+	Level     Level
 	Message   string
 	LatencyMS int64
 	CheckedAt time.Time
 	URL       string
 	Expect    int
+	Interval  string
+	Schedule  string
+	Timeout   time.Duration
+	Warn      time.Duration // latency thresholds, zero when not set
+	Critical  time.Duration
+	NextRun   time.Time
+	sched     config.Schedule
+	id        uint64 // identifies the check when committing results
+	running   bool
+	downSince time.Time // when the check started failing, zero while passing
 }
 
 type HostStatus struct {
@@ -41,25 +67,54 @@ type State struct {
 	cfg        *config.Config
 	hosts      map[string]*HostStatus // key: host name
 	configPath string
+	interval   time.Duration // default for checks without their own schedule
+	sem        chan struct{} // limits how many checks run at once
+	targets    *targetLimiter
+	notifiers  *notify.Registry
+	dashboard  string // linked from notifications
+	nextID     uint64
+	runs       sync.WaitGroup // the scheduler loop and the checks it started
+	sends      sync.WaitGroup // notifications in flight
 }
 
+// schedulerTick is how often the scheduler looks for due checks.
+const schedulerTick = time.Second
+
 func New(cfg *config.Config) *State {
-	st := &State{cfg: cfg, hosts: make(map[string]*HostStatus)}
+	parallelism := cfg.Parallelism
+	if parallelism <= 0 {
+		parallelism = config.DefaultParallelism
+	}
+	st := &State{cfg: cfg, hosts: make(map[string]*HostStatus), sem: make(chan struct{}, parallelism), notifiers: notify.Default()}
+	concurrency, interval, _ := config.TargetLimits(cfg)
+	st.targets = newTargetLimiter(concurrency, interval)
 	for _, h := range cfg.Hosts {
 		hs := &HostStatus{Name: h.Name, Address: h.Address, HCURL: h.HealthchecksPingURL}
 		for _, c := range h.Checks {
-			cs := CheckStatus{Type: c.Type, Enabled: c.Enabled}
-			if c.Type == config.CheckHTTP {
-				cs.URL = c.URL
-				cs.Expect = c.Expect
-			}
-			hs.Checks = append(hs.Checks, cs)
+			hs.Checks = append(hs.Checks, st.newCheckLocked(c))
 		}
 		st.hosts[h.Name] = hs
 	}
 	return st
 }
 
+// newCheckLocked builds the runtime status for a configured check.
+func (s *State) newCheckLocked(c config.Check) CheckStatus {
+	s.nextID++
+	cs := CheckStatus{Type: c.Type, Enabled: c.Enabled, Interval: c.Interval, Schedule: c.Schedule, id: s.nextID}
+	if c.Type == config.CheckHTTP {
+		cs.URL = c.URL
+		cs.Expect = c.Expect
+	}
+	timeout, err := config.CheckTimeout(c)
+	if err != nil {
+		timeout, _ = config.CheckTimeout(config.Check{Type: c.Type})
+	}
+	cs.Timeout = timeout
+	cs.Warn, cs.Critical, _ = config.LatencyThresholds(c)
+	return cs
+}
+
 func (s *State) Snapshot() []*HostStatus {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -84,13 +139,14 @@ func (s *State) AddHost(name, address, hcurl string) error {
 	if _, exists := s.hosts[name]; exists {
 		return fmt.Errorf("host exists")
 	}
+	check := config.Check{Type: config.CheckPing, Enabled: true}
 	hs := &HostStatus{Name: name, Address: address, HCURL: hcurl}
-	hs.Checks = append(hs.Checks, CheckStatus{Type: config.CheckPing, Enabled: true})
+	hs.Checks = append(hs.Checks, s.newCheckLocked(check))
 	s.hosts[name] = hs
 	// update cfg
 	s.cfg.Hosts = append(s.cfg.Hosts, config.Host{
 		Name: name, Address: address, HealthchecksPingURL: hcurl,
-		Checks: []config.Check{{Type: config.CheckPing, Enabled: true}},
+		Checks: []config.Check{check},
 	})
 	return s.saveConfigLocked()
 }
@@ -144,12 +200,13 @@ func (s *State) AddHTTPCheck(hostName, url string, expect int) error {
 	if !ok {
 		return fmt.Errorf("host not found")
 	}
+	check := config.Check{Type: config.CheckHTTP, Enabled: true, URL: url, Expect: expect}
 	// append to runtime
-	hs.Checks = append(hs.Checks, CheckStatus{Type: config.CheckHTTP, Enabled: true, URL: url, Expect: expect})
+	hs.Checks = append(hs.Checks, s.newCheckLocked(check))
 	// append to cfg
 	for i := range s.cfg.Hosts {
 		if s.cfg.Hosts[i].Name == hostName {
-			s.cfg.Hosts[i].Checks = append(s.cfg.Hosts[i].Checks, config.Check{Type: config.CheckHTTP, Enabled: true, URL: url, Expect: expect})
+			s.cfg.Hosts[i].Checks = append(s.cfg.Hosts[i].Checks, check)
 			break
 		}
 	}
@@ -180,10 +237,11 @@ func (s *State) AddPingCheck(hostName string) error {
 	if !ok {
 		return fmt.Errorf("host not found")
 	}
-	hs.Checks = append(hs.Checks, CheckStatus{Type: config.CheckPing, Enabled: true})
+	check := config.Check{Type: config.CheckPing, Enabled: true}
+	hs.Checks = append(hs.Checks, s.newCheckLocked(check))
 	for i := range s.cfg.Hosts {
 		if s.cfg.Hosts[i].Name == hostName {
-			s.cfg.Hosts[i].Checks = append(s.cfg.Hosts[i].Checks, config.Check{Type: config.CheckPing, Enabled: true})
+			s.cfg.Hosts[i].Checks = append(s.cfg.Hosts[i].Checks, check)
 			break
 		}
 	}
@@ -288,17 +346,27 @@ func (s *State) SetHCURL(hostName, url string) {
 	}
 }
 
+// StartScheduler runs each enabled check on its own interval or cron schedule.
+// interval is the default for checks that set neither. Closing stop starts no
+// further checks; checks waiting for a slot give up, and Wait returns once the
+// ones already running have finished.
 func (s *State) StartScheduler(interval time.Duration, stop <-chan struct{}) {
+	s.mu.Lock()
+	s.interval = interval
+	s.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.runs.Add(1)
 	go func() {
-		// run immediately, then on each tick
-		s.runOnce()
-		t := time.NewTicker(interval)
+		defer s.runs.Done()
+		defer cancel()
+		// run due checks immediately, then on each tick
+		s.runOnce(ctx)
+		t := time.NewTicker(schedulerTick)
 		defer t.Stop()
 		for {
 			select {
 			case <-t.C:
-				fmt.Println("scheduler tick")
-				s.runOnce()
+				s.runOnce(ctx)
 			case <-stop:
 				return
 			}
@@ -306,63 +374,330 @@ func (s *State) StartScheduler(interval time.Duration, stop <-chan struct{}) {
 	}()
 }
 
-func (s *State) runOnce() {
-	fmt.Println("running checks")
+// Wait blocks until the scheduler has stopped and the checks it started have
+// finished, then flushes notifiers holding events back, such as email
+// digests, and waits until every notification has been sent or given up on.
+func (s *State) Wait() {
+	s.runs.Wait()
+	s.mu.RLock()
+	notifiers := s.notifiers
+	s.mu.RUnlock()
+	notifiers.Flush()
+	s.sends.Wait()
+}
+
+// SetNotifiers replaces the registry used to send notifications.
+func (s *State) SetNotifiers(r *notify.Registry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifiers = r
+}
+
+// SetDashboardURL sets the dashboard address linked from notifications when
+// the config sets no dashboard_url.
+func (s *State) SetDashboardURL(url string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dashboard = url
+}
+
+// job is a snapshot of everything needed to run one check without s.mu.
+type job struct {
+	host     string
+	address  string
+	hcurl    string
+	channels []config.Channel // notification channels of the host
+	check    CheckStatus
+}
 
+// result is the outcome of a check, committed back to its CheckStatus.
+type result struct {
+	Level     Level
+	Message   string
+	LatencyMS int64
+	CheckedAt time.Time
+}
+
+// runOnce starts every due check that is not already running. Due checks are
+// snapshotted under the lock and run concurrently without holding it, at most
+// cap(s.sem) at a time and within the per-target limits, so a slow host
+// delays neither other checks nor the UI.
+func (s *State) runOnce(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	for _, j := range s.dueJobs(time.Now()) {
+		s.runs.Add(1)
+		go func() {
+			defer s.runs.Done()
+			s.run(ctx, j)
+		}()
+	}
+}
+
+func (s *State) dueJobs(now time.Time) []job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []job
 	for _, hs := range s.hosts {
 		for i := range hs.Checks {
 			c := &hs.Checks[i]
 			if !c.Enabled {
+				c.NextRun = time.Time{}
+				continue
+			}
+			if c.running || !s.dueLocked(hs.Name, c, now) {
+				continue
+			}
+			c.running = true
+			jobs = append(jobs, job{host: hs.Name, address: hs.Address, hcurl: hs.HCURL, channels: s.channelsLocked(hs.Name), check: *c})
+		}
+	}
+	return jobs
+}
+
+// run runs a check once it gets a slot and commits its result. It gives up
+// without running the check if ctx is done while it waits.
+func (s *State) run(ctx context.Context, j job) {
+	// wait for the target before taking a slot, so checks queued behind a
+	// busy target do not hold up checks against other targets
+	release, err := s.targets.acquire(ctx, targetKey(j))
+	if err != nil {
+		s.abandon(j.check.id)
+		return
+	}
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		release()
+		s.abandon(j.check.id)
+		return
+	}
+	res := execute(j)
+	<-s.sem
+	release()
+
+	if prev, downSince, ok := s.commit(j.check.id, res); ok {
+		s.notify(j, prev, downSince, res)
+	}
+}
+
+// notify sends a result to Healthchecks.io, for ping checks of hosts with a
+// ping URL, and to the host's channels when the check starts failing or
+// recovers. Sends run in the background so slow channels do not hold up
+// checks; Wait waits for them. downSince is when the check started failing
+// before this result.
+func (s *State) notify(j job, prev Level, downSince time.Time, res result) {
+	ev := notify.Event{
+		Host:      j.host,
+		Address:   j.address,
+		Check:     j.check.Type,
+		URL:       j.check.URL,
+		Level:     string(res.Level),
+		Previous:  string(prev),
+		Failed:    res.Level.Failed(),
+		Message:   res.Message,
+		LatencyMS: res.LatencyMS,
+		CheckedAt: res.CheckedAt,
+	}
+	if !ev.Failed && !downSince.IsZero() {
+		ev.Outage = res.CheckedAt.Sub(downSince)
+		ev.OutageSeconds = int64(ev.Outage.Seconds())
+	}
+	var channels []config.Channel
+	if j.check.Type == config.CheckPing && j.hcurl != "" {
+		channels = append(channels, config.Channel{Name: "healthchecks.io", Type: config.ChannelHealthchecks, URL: j.hcurl})
+	}
+	if notable(prev, res.Level) {
+		channels = append(channels, j.channels...)
+	}
+	s.mu.RLock()
+	notifiers := s.notifiers
+	ev.DashboardURL = s.cfg.DashboardURL
+	if ev.DashboardURL == "" {
+		ev.DashboardURL = s.dashboard
+	}
+	s.mu.RUnlock()
+	for _, ch := range channels {
+		s.sends.Add(1)
+		go func(ch config.Channel) {
+			defer s.sends.Done()
+			if err := notifiers.Notify(context.Background(), ch, ev); err != nil {
+				log.Printf("notify %s for host %s %s check: %v", ch.Name, j.host, j.check.Type, err)
+			}
+		}(ch)
+	}
+}
+
+// notable reports whether a check started failing, including on its first
+// run, or recovered.
+func notable(prev, cur Level) bool {
+	if prev == "" {
+		return cur.Failed()
+	}
+	return prev.Failed() != cur.Failed()
+}
+
+// channelsLocked returns the notification channels a host notifies.
+func (s *State) channelsLocked(host string) []config.Channel {
+	var names []string
+	for _, h := range s.cfg.Hosts {
+		if h.Name == host {
+			names = h.Notify
+			break
+		}
+	}
+	var channels []config.Channel
+	for _, name := range names {
+		for _, ch := range s.cfg.Notifications {
+			if ch.Name == name {
+				channels = append(channels, ch)
+			}
+		}
+	}
+	return channels
+}
+
+func execute(j job) result {
+	return applyThresholds(j.check, probe(j))
+}
+
+// probe runs a check and reports its own verdict, before latency thresholds.
+func probe(j job) result {
+	c := j.check
+	switch c.Type {
+	case config.CheckPing:
+		res := checks.PingOnce(j.address, c.Timeout)
+		if !res.OK {
+			msg := "no reply"
+			if res.Err != nil {
+				msg = res.Err.Error()
+			}
+			return result{Level: LevelCritical, Message: msg, CheckedAt: time.Now()}
+		}
+		if res.PacketsRx < res.PacketsTx {
+			msg := fmt.Sprintf("pong, %d of %d packets lost", res.PacketsTx-res.PacketsRx, res.PacketsTx)
+			return result{Level: LevelWarning, Message: msg, LatencyMS: res.Latency.Milliseconds(), CheckedAt: time.Now()}
+		}
+		return result{Level: LevelOK, Message: "pong", LatencyMS: res.Latency.Milliseconds(), CheckedAt: time.Now()}
+	case config.CheckHTTP:
+		url := c.URL
+		if url == "" {
+			// fallback to http://address if URL not set
+			url = "http://" + j.address
+		}
+		res := checks.HTTPGet(url, c.Timeout)
+		if res.Err != nil {
+			return result{Level: LevelCritical, Message: res.Err.Error(), CheckedAt: time.Now()}
+		}
+		expect := c.Expect
+		if expect == 0 {
+			expect = 200
+		}
+		level := LevelOK
+		if res.Code != expect {
+			level = LevelCritical
+		}
+		return result{
+			Level:     level,
+			Message:   fmt.Sprintf("status %d (expect %d)", res.Code, expect),
+			LatencyMS: res.Latency.Milliseconds(),
+			CheckedAt: time.Now(),
+		}
+	}
+	return result{Level: LevelUnknown, Message: fmt.Sprintf("unknown check type %q", c.Type), CheckedAt: time.Now()}
+}
+
+// applyThresholds downgrades a passing result that took longer than the
+// check's warn or critical latency.
+func applyThresholds(c CheckStatus, res result) result {
+	if res.Level.Failed() {
+		return res
+	}
+	latency := time.Duration(res.LatencyMS) * time.Millisecond
+	switch {
+	case c.Critical > 0 && latency > c.Critical:
+		res.Level = LevelCritical
+		res.Message = fmt.Sprintf("%s (latency %v over critical %v)", res.Message, latency, c.Critical)
+	case c.Warn > 0 && latency > c.Warn && res.Level == LevelOK:
+		res.Level = LevelWarning
+		res.Message = fmt.Sprintf("%s (latency %v over warn %v)", res.Message, latency, c.Warn)
+	}
+	return res
+}
+
+// commit writes a result back to the check it was run for in a single
+// critical section and returns the previous level and when the check started
+// failing before this result. The check is found by id, so results for checks
+// that were removed while running are dropped (ok is false) and renamed hosts
+// still get theirs.
+func (s *State) commit(id uint64, res result) (prev Level, downSince time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, hs := range s.hosts {
+		for i := range hs.Checks {
+			c := &hs.Checks[i]
+			if c.id != id {
 				continue
 			}
-			switch c.Type {
-			case config.CheckPing:
-				res := checks.PingOnce(hs.Address, 2*time.Second)
-				c.OK = res.OK
-				c.CheckedAt = time.Now()
-				if res.OK {
-					c.Message = "pong"
-					c.LatencyMS = res.Latency.Milliseconds()
-					if hs.HCURL != "" {
-						_ = notifyHealthchecksOK(hs.HCURL)
-					}
-				} else {
-					if res.Err != nil {
-						c.Message = res.Err.Error()
-					} else {
-						c.Message = "no reply"
-					}
-					c.LatencyMS = 0
-					if hs.HCURL != "" {
-						_ = notifyHealthchecksFail(hs.HCURL)
-					}
-				}
-			case config.CheckHTTP:
-				url := c.URL
-				if url == "" {
-					// fallback to http://address if URL not set
-					url = "http://" + hs.Address
-				}
-				res := checks.HTTPGet(url, 5*time.Second)
-				c.CheckedAt = time.Now()
-				if res.Err != nil {
-					c.OK = false
-					c.Message = res.Err.Error()
-					c.LatencyMS = 0
-				} else {
-					expect := c.Expect
-					if expect == 0 {
-						expect = 200
-					}
-					c.OK = (res.Code == expect)
-					c.Message = fmt.Sprintf("status %d (expect %d)", res.Code, expect)
-					c.LatencyMS = res.Latency.Milliseconds()
-				}
+			prev, downSince = c.Level, c.downSince
+			switch {
+			case !res.Level.Failed():
+				c.downSince = time.Time{}
+			case c.downSince.IsZero():
+				c.downSince = res.CheckedAt
 			}
+			c.Level = res.Level
+			c.Message = res.Message
+			c.LatencyMS = res.LatencyMS
+			c.CheckedAt = res.CheckedAt
+			c.running = false
+			return prev, downSince, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// abandon marks a check that was due but did not run as no longer running.
+func (s *State) abandon(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, hs := range s.hosts {
+		for i := range hs.Checks {
+			if hs.Checks[i].id == id {
+				hs.Checks[i].running = false
+				return
+			}
+		}
+	}
+}
+
+// dueLocked reports whether c should run at now and, if so, advances its next
+// run. Interval checks are due straight away, cron checks at their first slot.
+func (s *State) dueLocked(host string, c *CheckStatus, now time.Time) bool {
+	if c.sched == nil {
+		sched, err := config.ParseSchedule(config.Check{Interval: c.Interval, Schedule: c.Schedule}, s.interval)
+		if err != nil {
+			log.Printf("host %s %s check: %v; using default interval %v", host, c.Type, err, s.interval)
+			sched, _ = config.ParseSchedule(config.Check{}, s.interval)
+		}
+		c.sched = sched
+	}
+	if c.NextRun.IsZero() {
+		// interval checks start at their phase within the first interval so
+		// checks sharing an interval do not all fire on the same tick
+		c.NextRun = c.sched.Next(now)
+		if config.IsInterval(c.sched) {
+			c.NextRun = now.Add(phase(host, c, c.NextRun.Sub(now)))
 		}
 	}
+	// a schedule without a next time never fires
+	if c.NextRun.IsZero() || now.Before(c.NextRun) {
+		return false
+	}
+	c.NextRun = c.sched.Next(now)
+	return true
 }
 
 func (s *State) saveConfigLocked() error {
@@ -415,31 +750,3 @@ func (s *State) saveConfigLocked() error {
 		return nil
 	}
 }
-
-func notifyHealthchecksFail(base string) error {
-	client := &http.Client{Timeout: 5 * time.Second}
-	url := base
-	if url != "" && url[len(url)-1] != '/' {
-		url += "/fail"
-	} else {
-		url += "fail"
-	}
-	req, _ := http.NewRequest(http.MethodGet, url, nil)
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	_ = resp.Body.Close()
-	return nil
-}
-
-func notifyHealthchecksOK(base string) error {
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, _ := http.NewRequest(http.MethodGet, base, nil)
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	_ = resp.Body.Close()
-	return nil
-}
@@ -0,0 +1,249 @@
+package state
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/config"
+	"github.com/andrewsjg/simple-healthchecker/copilot/internal/notify"
+)
+
+// slowServer answers every request after delay and records the most requests
+// it served at once.
+func slowServer(t *testing.T, delay time.Duration) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	var active, peak, total atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		total.Add(1)
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		active.Add(-1)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &peak, &total
+}
+
+// newTestState returns a state with n http checks against url, all due now.
+func newTestState(parallelism, n int, url string) *State {
+	host := config.Host{Name: "web", Address: "127.0.0.1"}
+	for i := 0; i < n; i++ {
+		host.Checks = append(host.Checks, config.Check{Type: config.CheckHTTP, Enabled: true, URL: url, Interval: "1m"})
+	}
+	st := New(&config.Config{Parallelism: parallelism, Hosts: []config.Host{host}})
+	for i := range st.hosts["web"].Checks {
+		st.hosts["web"].Checks[i].NextRun = time.Now().Add(-time.Second)
+	}
+	return st
+}
+
+func TestRunOnceLimitsConcurrency(t *testing.T) {
+	srv, peak, _ := slowServer(t, 50*time.Millisecond)
+	st := newTestState(2, 6, srv.URL)
+
+	// read the state while checks commit their results
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				st.Snapshot()
+			}
+		}
+	}()
+
+	st.runOnce(context.Background())
+	st.Wait()
+	close(done)
+	readers.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want 2", got)
+	}
+	hs, _ := st.GetHost("web")
+	for i, c := range hs.Checks {
+		if c.Level != LevelOK || c.Message != "status 200 (expect 200)" || c.CheckedAt.IsZero() || c.running {
+			t.Errorf("check %d = %s %q running=%v, want a committed OK result", i, c.Level, c.Message, c.running)
+		}
+	}
+}
+
+func TestRunOnceSkipsRunningChecks(t *testing.T) {
+	srv, _, total := slowServer(t, 50*time.Millisecond)
+	st := newTestState(4, 1, srv.URL)
+
+	st.runOnce(context.Background())
+	st.mu.Lock()
+	st.hosts["web"].Checks[0].NextRun = time.Now().Add(-time.Second)
+	st.mu.Unlock()
+	st.runOnce(context.Background()) // still running, not started again
+	st.Wait()
+
+	if got := total.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRunDropsResultOfRemovedCheck(t *testing.T) {
+	srv, _, _ := slowServer(t, 50*time.Millisecond)
+	st := newTestState(4, 2, srv.URL)
+	st.mu.Lock()
+	st.hosts["web"].Checks[1].NextRun = time.Now().Add(time.Hour)
+	st.mu.Unlock()
+
+	st.runOnce(context.Background())
+	if err := st.RemoveCheck("web", 0); err != nil {
+		t.Fatal(err)
+	}
+	st.Wait()
+
+	hs, _ := st.GetHost("web")
+	if len(hs.Checks) != 1 || !hs.Checks[0].CheckedAt.IsZero() {
+		t.Errorf("checks = %+v, want the remaining check without a result", hs.Checks)
+	}
+}
+
+func TestRunGivesUpWhenStopped(t *testing.T) {
+	srv, _, total := slowServer(t, 0)
+	st := newTestState(1, 1, srv.URL)
+	st.sem <- struct{}{} // every slot is taken
+
+	ctx, cancel := context.WithCancel(context.Background())
+	st.runOnce(ctx)
+	cancel()
+
+	waited := make(chan struct{})
+	go func() {
+		st.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the context was cancelled")
+	}
+
+	hs, _ := st.GetHost("web")
+	if c := hs.Checks[0]; c.running || !c.CheckedAt.IsZero() || total.Load() != 0 {
+		t.Errorf("check running=%v checked=%v requests=%d, want it abandoned without running", c.running, c.CheckedAt, total.Load())
+	}
+}
+
+func TestStartSchedulerStops(t *testing.T) {
+	st := New(&config.Config{})
+	stop := make(chan struct{})
+	st.StartScheduler(time.Minute, stop)
+	close(stop)
+
+	waited := make(chan struct{})
+	go func() {
+		st.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(2 * schedulerTick):
+		t.Fatal("Wait did not return after stop was closed")
+	}
+}
+
+// slowNotifier records events after a delay.
+type slowNotifier struct {
+	delay time.Duration
+	mu    sync.Mutex
+	sent  []notify.Event
+}
+
+func (n *slowNotifier) Type() config.ChannelType         { return "slow" }
+func (n *slowNotifier) Validate(ch config.Channel) error { return nil }
+func (n *slowNotifier) Notify(ctx context.Context, ch config.Channel, ev notify.Event) error {
+	time.Sleep(n.delay)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, ev)
+	return nil
+}
+
+// digestNotifier holds events back until it is flushed, like an email digest.
+type digestNotifier struct {
+	flushed chan struct{}
+	once    sync.Once
+	sent    atomic.Int32
+}
+
+func (n *digestNotifier) Type() config.ChannelType         { return "digest" }
+func (n *digestNotifier) Validate(ch config.Channel) error { return nil }
+func (n *digestNotifier) Flush()                           { n.once.Do(func() { close(n.flushed) }) }
+func (n *digestNotifier) Notify(ctx context.Context, ch config.Channel, ev notify.Event) error {
+	<-n.flushed
+	n.sent.Add(1)
+	return nil
+}
+
+func TestWaitFlushesHeldNotifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	st := newTestState(1, 1, srv.URL)
+	st.cfg.Notifications = []config.Channel{{Name: "mail", Type: "digest"}}
+	st.cfg.Hosts[0].Notify = []string{"mail"}
+	n := &digestNotifier{flushed: make(chan struct{})}
+	r := notify.NewRegistry()
+	r.Register(n)
+	st.SetNotifiers(r)
+
+	st.runOnce(context.Background())
+	waited := make(chan struct{})
+	go func() {
+		st.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not flush the pending digest")
+	}
+	if got := n.sent.Load(); got != 1 {
+		t.Errorf("sent = %d, want the held failure sent", got)
+	}
+}
+
+func TestWaitForNotifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	st := newTestState(1, 1, srv.URL)
+	st.cfg.Notifications = []config.Channel{{Name: "ops", Type: "slow"}}
+	st.cfg.Hosts[0].Notify = []string{"ops"}
+	n := &slowNotifier{delay: 100 * time.Millisecond}
+	r := notify.NewRegistry()
+	r.Register(n)
+	st.SetNotifiers(r)
+
+	st.runOnce(context.Background())
+	st.Wait()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.sent) != 1 || !n.sent[0].Failed || n.sent[0].Host != "web" {
+		t.Errorf("sent = %+v, want the failure sent before Wait returns", n.sent)
+	}
+}